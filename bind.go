@@ -1,15 +1,23 @@
 package echo
 
 import (
+	"bytes"
 	"encoding"
+	"encoding/base64"
 	"encoding/json"
 	"encoding/xml"
 	"errors"
 	"fmt"
+	"io"
+	"mime/multipart"
 	"net/http"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
+
+	gbytes "github.com/labstack/gommon/bytes"
 )
 
 type (
@@ -19,7 +27,29 @@ type (
 	}
 
 	// DefaultBinder is the default implementation of the Binder interface.
-	DefaultBinder struct{}
+	DefaultBinder struct {
+		// StrictJSON makes binding fail for JSON bodies with fields which don't
+		// map to any field of the target, e.g. because of client typos. JSON
+		// values must always match the type of their field. It has no effect
+		// with an `Echo#JSONSerializer`.
+		StrictJSON bool
+
+		// BodyLimit is the maximum size of request bodies the binder decodes,
+		// e.g. "2M". Larger bodies make binding fail with "413 - Request Entity
+		// Too Large", also when their size isn't known upfront.
+		// Optional. Default value "", which means no limit.
+		BodyLimit string
+
+		// CollectErrors makes binding path params, query params, headers and
+		// form fields go on after a field fails to bind, so it fails with all
+		// the `FieldErrors` at once.
+		CollectErrors bool
+
+		decoders map[string]DecodeFunc
+	}
+
+	// DecodeFunc decodes a request body into i.
+	DecodeFunc func(r io.Reader, i interface{}) error
 
 	// BindUnmarshaler is the interface used to wrap the UnmarshalParam method.
 	// Types that don't implement this, but do implement encoding.TextUnmarshaler
@@ -30,75 +60,498 @@ type (
 	}
 )
 
-// Bind implements the `Binder#Bind` function.
-func (b *DefaultBinder) Bind(i interface{}, c Context) (err error) {
-	req := c.Request()
+// WithBinder returns a route-level middleware which makes `Context#Bind()` use
+// the provided binder instead of `Echo#Binder` for the route.
+//
+// Example:
+//
+//	e.POST("/upload", h, echo.WithBinder(csvBinder))
+func WithBinder(b Binder) MiddlewareFunc {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			c.matched().binder = b
+			return next(c)
+		}
+	}
+}
+
+// Bind implements the `Binder#Bind` function. Path params, query params and
+// request headers are bound into the fields tagged with `param`, `query` and
+// `header` respectively, followed by the request body based on Content-Type
+// header. Files uploaded as multipart/form-data are bound into
+// `*multipart.FileHeader` and `[]*multipart.FileHeader` fields.
+func (b *DefaultBinder) Bind(i interface{}, c Context) error {
+	_, err := b.bind(i, c)
+	return err
+}
 
+// Register registers a decoder for request bodies of the MIME type, e.g. for
+// MessagePack or YAML. Registered decoders take precedence over the built-in
+// JSON, XML and form decoding. It isn't safe to call while serving requests.
+//
+// Example:
+//
+//	e.Binder.(*echo.DefaultBinder).Register("application/x-yaml", func(r io.Reader, i interface{}) error {
+//		return yaml.NewDecoder(r).Decode(i)
+//	})
+func (b *DefaultBinder) Register(mime string, fn DecodeFunc) {
+	if b.decoders == nil {
+		b.decoders = map[string]DecodeFunc{}
+	}
+	b.decoders[strings.ToLower(mime)] = fn
+}
+
+// decoder returns the decoder registered for the MIME type of the content
+// type, if any.
+func (b *DefaultBinder) decoder(ctype string) DecodeFunc {
+	if len(b.decoders) == 0 {
+		return nil
+	}
+	if i := strings.IndexByte(ctype, ';'); i >= 0 {
+		ctype = ctype[:i]
+	}
+	return b.decoders[strings.ToLower(strings.TrimSpace(ctype))]
+}
+
+// BindPathParams binds path params into the fields of i tagged with `param`.
+func (b *DefaultBinder) BindPathParams(i interface{}, c Context) error {
 	names := c.ParamNames()
 	values := c.ParamValues()
 	params := map[string][]string{}
 	for i, name := range names {
 		params[name] = []string{values[i]}
 	}
-	if err := b.bindData(i, params, "param"); err != nil {
-		return NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+	return b.bindSource(i, params, "param")
+}
+
+// BindQueryParams binds query params into the fields of i tagged with `query`.
+func (b *DefaultBinder) BindQueryParams(i interface{}, c Context) error {
+	return b.bindSource(i, c.QueryParams(), "query")
+}
+
+// BindHeaders binds request headers into the fields of i tagged with `header`.
+func (b *DefaultBinder) BindHeaders(i interface{}, c Context) error {
+	return b.bindSource(i, c.Request().Header, "header")
+}
+
+// BindBody binds the request body into i based on Content-Type header. Form
+// fields are bound into the fields tagged with `form`.
+func (b *DefaultBinder) BindBody(i interface{}, c Context) error {
+	if err := validateBindTarget(i); err != nil {
+		return NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+	}
+	if err := setDefaults(i); err != nil {
+		return NewHTTPError(http.StatusInternalServerError, err.Error()).SetInternal(err)
+	}
+	if c.Request().ContentLength == 0 {
+		return nil
+	}
+	var errs *FieldErrors
+	if b.CollectErrors {
+		errs = new(FieldErrors)
 	}
-	if err = b.bindData(i, c.QueryParams(), "query"); err != nil {
+	if _, err := b.bindBody(i, c, &bindValues{}, errs); err != nil {
+		return err
+	}
+	if errs != nil && len(*errs) > 0 {
+		return fieldErrorsHTTPError(*errs)
+	}
+	return nil
+}
+
+func (b *DefaultBinder) bindSource(i interface{}, data map[string][]string, tag string) error {
+	if err := validateBindTarget(i); err != nil {
+		return NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+	}
+	if err := validateValuesTarget(i); err != nil {
+		return NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+	}
+	if err := setDefaults(i); err != nil {
+		return NewHTTPError(http.StatusInternalServerError, err.Error()).SetInternal(err)
+	}
+	values := &bindValues{data: data}
+	if b.CollectErrors {
+		values.errs = new(FieldErrors)
+	}
+	if err := b.bindValues(i, values, tag); err != nil {
 		return NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
 	}
+	if values.errs != nil && len(*values.errs) > 0 {
+		return fieldErrorsHTTPError(*values.errs)
+	}
+	return nil
+}
+
+// BindUnconsumed binds the request like `Bind()`, and also returns the sorted
+// names of the query params and form fields which didn't map to any field of i,
+// so handlers can reject unexpected input.
+//
+// Example:
+//
+//	unknown, err := new(echo.DefaultBinder).BindUnconsumed(u, c)
+//	if err == nil && len(unknown) > 0 {
+//		err = echo.NewHTTPError(http.StatusBadRequest, "unexpected fields: "+strings.Join(unknown, ", "))
+//	}
+func (b *DefaultBinder) BindUnconsumed(i interface{}, c Context) ([]string, error) {
+	return b.bind(i, c)
+}
+
+func (b *DefaultBinder) bind(i interface{}, c Context) (unconsumed []string, err error) {
+	if err = validateBindTarget(i); err != nil {
+		return nil, NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+	}
+	req := c.Request()
+
+	// Defaults are set first, so they remain for fields without a value in
+	// any of the sources bound below.
+	if err = setDefaults(i); err != nil {
+		return nil, NewHTTPError(http.StatusInternalServerError, err.Error()).SetInternal(err)
+	}
+
+	// Field errors are reported together once binding is done, unless
+	// decoding the body fails.
+	var errs *FieldErrors
+	if b.CollectErrors {
+		errs = new(FieldErrors)
+		defer func() {
+			if err == nil && len(*errs) > 0 {
+				unconsumed, err = nil, fieldErrorsHTTPError(*errs)
+			}
+		}()
+	}
+
+	// Other types than structs, maps and slices, e.g. `*interface{}`, can
+	// only be decoded from a body.
+	verr := validateValuesTarget(i)
+	if verr != nil && req.ContentLength == 0 {
+		return nil, NewHTTPError(http.StatusInternalServerError).SetInternal(verr)
+	}
+
+	// Top-level slices can only be decoded from a JSON or XML body, so params
+	// and query params are ignored for them unless there is no body.
+	query := &bindValues{data: c.QueryParams(), errs: errs}
+	if verr == nil && (req.ContentLength == 0 || !isSliceTarget(i)) {
+		names := c.ParamNames()
+		values := c.ParamValues()
+		params := map[string][]string{}
+		for i, name := range names {
+			params[name] = []string{values[i]}
+		}
+		if err = b.bindValues(i, &bindValues{data: params, errs: errs}, "param"); err != nil {
+			return nil, NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		}
+		if err = b.bindValues(i, query, "query"); err != nil {
+			return nil, NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		}
+		if err = b.bindValues(i, &bindValues{data: req.Header, errs: errs}, "header"); err != nil {
+			return nil, NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		}
+	}
+	unconsumed = query.unconsumed()
 	if req.ContentLength == 0 {
 		return
 	}
+	return b.bindBody(i, c, query, errs)
+}
+
+// bindBody binds the request body into i. The query values are those bound
+// before, to tell which form fields are unconsumed.
+func (b *DefaultBinder) bindBody(i interface{}, c Context, query *bindValues, errs *FieldErrors) (unconsumed []string, err error) {
+	req := c.Request()
+	if b.BodyLimit != "" {
+		limit, perr := gbytes.Parse(b.BodyLimit)
+		if perr != nil {
+			return nil, NewHTTPError(http.StatusInternalServerError).SetInternal(fmt.Errorf("invalid binder body limit=%s", b.BodyLimit))
+		}
+		if req.ContentLength > limit {
+			return nil, ErrStatusRequestEntityTooLarge
+		}
+		// The body may be larger than announced or of unknown size, in which
+		// case reading it fails once the limit is exceeded.
+		lb := &limitedBody{ReadCloser: req.Body, remaining: limit}
+		req.Body = lb
+		defer func() {
+			if lb.exceeded {
+				unconsumed, err = nil, ErrStatusRequestEntityTooLarge
+			}
+		}()
+	}
 	ctype := req.Header.Get(HeaderContentType)
+	if decode := b.decoder(ctype); decode != nil {
+		if err = decode(req.Body, i); err != nil {
+			return nil, NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		}
+		return
+	}
 	switch {
+	case strings.HasPrefix(ctype, MIMEApplicationJSON) && c.Echo().JSONSerializer != nil:
+		if err = c.Echo().JSONSerializer.Deserialize(req.Body, i); err != nil {
+			return nil, NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		}
 	case strings.HasPrefix(ctype, MIMEApplicationJSON):
-		if err = json.NewDecoder(req.Body).Decode(i); err != nil {
+		// The decoder reports only the first of the fields having a value of
+		// the wrong type, so the body is kept to look for the other ones.
+		var body *bytes.Buffer
+		r := io.Reader(req.Body)
+		if reflect.TypeOf(i).Elem().Kind() == reflect.Struct {
+			body = new(bytes.Buffer)
+			r = io.TeeReader(r, body)
+		}
+		dec := json.NewDecoder(r)
+		if b.StrictJSON {
+			dec.DisallowUnknownFields()
+		}
+		if err = dec.Decode(i); err != nil {
 			if ute, ok := err.(*json.UnmarshalTypeError); ok {
-				return NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Unmarshal type error: expected=%v, got=%v, field=%v, offset=%v", ute.Type, ute.Value, ute.Field, ute.Offset)).SetInternal(err)
+				if body != nil {
+					if be := jsonTypeErrors(body.Bytes(), i); len(be.Errors) > 1 {
+						return nil, NewHTTPError(http.StatusBadRequest, be.Error()).SetInternal(be)
+					}
+				}
+				return nil, NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Unmarshal type error: expected=%v, got=%v, field=%v, offset=%v", ute.Type, ute.Value, ute.Field, ute.Offset)).SetInternal(err)
 			} else if se, ok := err.(*json.SyntaxError); ok {
-				return NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Syntax error: offset=%v, error=%v", se.Offset, se.Error())).SetInternal(err)
+				return nil, NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Syntax error: offset=%v, error=%v", se.Offset, se.Error())).SetInternal(err)
+			} else if field := strings.TrimPrefix(err.Error(), "json: unknown field "); field != err.Error() {
+				return nil, NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Unknown field error: field=%v", field)).SetInternal(err)
 			}
-			return NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+			return nil, NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
 		}
 	case strings.HasPrefix(ctype, MIMEApplicationXML), strings.HasPrefix(ctype, MIMETextXML):
 		if err = xml.NewDecoder(req.Body).Decode(i); err != nil {
 			if ute, ok := err.(*xml.UnsupportedTypeError); ok {
-				return NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Unsupported type error: type=%v, error=%v", ute.Type, ute.Error())).SetInternal(err)
+				return nil, NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Unsupported type error: type=%v, error=%v", ute.Type, ute.Error())).SetInternal(err)
 			} else if se, ok := err.(*xml.SyntaxError); ok {
-				return NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Syntax error: line=%v, error=%v", se.Line, se.Error())).SetInternal(err)
+				return nil, NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Syntax error: line=%v, error=%v", se.Line, se.Error())).SetInternal(err)
 			}
-			return NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+			return nil, NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
 		}
 	case strings.HasPrefix(ctype, MIMEApplicationForm), strings.HasPrefix(ctype, MIMEMultipartForm):
+		if err = validateValuesTarget(i); err != nil {
+			return nil, NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+		}
 		params, err := c.FormParams()
 		if err != nil {
-			return NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+			return nil, NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		}
+		form := &bindValues{data: params, errs: errs}
+		if err = b.bindValues(i, form, "form"); err != nil {
+			return nil, NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
 		}
-		if err = b.bindData(i, params, "form"); err != nil {
-			return NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		if req.MultipartForm != nil {
+			bindFiles(i, req.MultipartForm.File)
 		}
+		// Form params include the query params, so a key is unconsumed only
+		// if neither of the bindings consumed it.
+		unconsumed = nil
+		for _, k := range form.unconsumed() {
+			if !query.consumed[k] {
+				unconsumed = append(unconsumed, k)
+			}
+		}
+		for _, k := range query.unconsumed() {
+			if _, ok := form.data[k]; !ok {
+				unconsumed = append(unconsumed, k)
+			}
+		}
+		sort.Strings(unconsumed)
 	default:
-		return ErrUnsupportedMediaType
+		return nil, ErrUnsupportedMediaType
 	}
 	return
 }
 
+// BindError is the error of decoding a JSON request body with several members
+// having a value of the wrong type. Unlike `BindingError`, it is about the
+// body only, whose members are decoded by `encoding/json`.
+type BindError struct {
+	Errors []*json.UnmarshalTypeError
+}
+
+// Error makes it compatible with `error` interface.
+func (e *BindError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, ute := range e.Errors {
+		msgs[i] = fmt.Sprintf("expected=%v, got=%v, field=%v", ute.Type, ute.Value, ute.Field)
+	}
+	return "Unmarshal type errors: " + strings.Join(msgs, "; ")
+}
+
+// jsonTypeErrors decodes the members of the JSON object in body one by one into
+// i, collecting the type errors of all the fields.
+func jsonTypeErrors(body []byte, i interface{}) *BindError {
+	be := new(BindError)
+	members := map[string]json.RawMessage{}
+	if err := json.NewDecoder(bytes.NewReader(body)).Decode(&members); err != nil {
+		return be
+	}
+	keys := make([]string, 0, len(members))
+	for k := range members {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		name, _ := json.Marshal(k)
+		member := append(append(append([]byte("{"), name...), ':'), members[k]...)
+		if err := json.Unmarshal(append(member, '}'), i); err != nil {
+			if ute, ok := err.(*json.UnmarshalTypeError); ok {
+				be.Errors = append(be.Errors, ute)
+			}
+		}
+	}
+	return be
+}
+
+// BindingError is the error of converting param, query, header or form values
+// into the type of a struct field, including failed `time_format`, `encoding`
+// and `oneof` tags. It is the internal error of the `HTTPError` returned by
+// `DefaultBinder`, unless errors are collected in `FieldErrors`.
+type BindingError struct {
+	// Field is the name of the struct field.
+	Field string `json:"field"`
+	// Values are the values which failed to convert.
+	Values []string `json:"values"`
+	// Expected is the type of the field, e.g. `int` or `[]time.Time`.
+	Expected string `json:"expected"`
+	Err      error  `json:"-"`
+}
+
+func newBindingError(field reflect.StructField, values []string, err error) *BindingError {
+	return &BindingError{
+		Field:    field.Name,
+		Values:   values,
+		Expected: field.Type.String(),
+		Err:      err,
+	}
+}
+
+// Error makes it compatible with `error` interface.
+func (e *BindingError) Error() string {
+	return fmt.Sprintf("invalid value %q for field %s, expected %s: %v",
+		strings.Join(e.Values, ","), e.Field, e.Expected, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *BindingError) Unwrap() error {
+	return e.Err
+}
+
+// FieldError is the error of binding a request value into a struct field, as
+// collected in `FieldErrors`. It adds the key and source of the value to the
+// error in Err, usually a `*BindingError`.
+type FieldError struct {
+	// Field is the key of the value, e.g. `page` or `items[0].name`.
+	Field string `json:"field"`
+	// Source is where the value comes from: "param", "query", "header" or
+	// "form".
+	Source  string `json:"source"`
+	Message string `json:"message"`
+	Err     error  `json:"-"`
+}
+
+// Error makes it compatible with `error` interface.
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s %s: %s", e.Source, e.Field, e.Message)
+}
+
+// Unwrap returns the underlying error.
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// FieldErrors are the errors of binding several fields, collected if
+// `DefaultBinder#CollectErrors` is set.
+type FieldErrors []*FieldError
+
+// Error makes it compatible with `error` interface.
+func (e FieldErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, fe := range e {
+		msgs[i] = fe.Error()
+	}
+	return "Bind errors: " + strings.Join(msgs, "; ")
+}
+
+func fieldErrorsHTTPError(errs FieldErrors) *HTTPError {
+	return NewHTTPError(http.StatusBadRequest, Map{"message": "Bind errors", "errors": errs}).SetInternal(errs)
+}
+
+// BindMap binds data into the struct pointed to by ptr using the same conversion
+// rules as `DefaultBinder`. Fields are matched by their `form` tag, falling back
+// to a case-insensitive match on the field name. It allows reusing the binding
+// logic outside of an HTTP request, e.g. with values from environment variables
+// or command line flags.
+func BindMap(ptr interface{}, data map[string][]string) error {
+	if err := validateBindTarget(ptr); err != nil {
+		return err
+	}
+	if err := setDefaults(ptr); err != nil {
+		return err
+	}
+	return new(DefaultBinder).bindData(ptr, data, "form")
+}
+
 func (b *DefaultBinder) bindData(ptr interface{}, data map[string][]string, tag string) error {
-	if ptr == nil || len(data) == 0 {
+	if err := validateBindTarget(ptr); err != nil {
+		return err
+	}
+	if err := validateValuesTarget(ptr); err != nil {
+		return err
+	}
+	return b.bindValues(ptr, &bindValues{data: data}, tag)
+}
+
+func (b *DefaultBinder) bindValues(ptr interface{}, values *bindValues, tag string) error {
+	data := values.data
+	if len(data) == 0 {
 		return nil
 	}
+	if err := validateBindTarget(ptr); err != nil {
+		return err
+	}
 	typ := reflect.TypeOf(ptr).Elem()
 	val := reflect.ValueOf(ptr).Elem()
 
+	// Headers are only bound into the struct fields tagged with them, as
+	// maps would get all of them. Neither headers nor path params, which any
+	// request may have, can be bound into slices.
+	switch typ.Kind() {
+	case reflect.Map:
+		if tag == "header" {
+			return nil
+		}
+	case reflect.Slice, reflect.Array:
+		if tag == "header" || tag == "param" {
+			return nil
+		}
+	}
+
 	if m, ok := ptr.(*map[string]interface{}); ok {
+		if *m == nil {
+			*m = map[string]interface{}{}
+		}
 		for k, v := range data {
 			(*m)[k] = v[0]
+			values.consume(k)
 		}
 		return nil
 	}
 
+	if m, ok := ptr.(*map[string][]string); ok {
+		if *m == nil {
+			*m = map[string][]string{}
+		}
+		for k, v := range data {
+			(*m)[k] = append([]string(nil), v...)
+			values.consume(k)
+		}
+		return nil
+	}
+
+	if k := typ.Kind(); k == reflect.Slice || k == reflect.Array {
+		return fmt.Errorf("%s data can't be bound into %s, binding element must be a struct or map", tag, typ)
+	}
 	if typ.Kind() != reflect.Struct {
-		return errors.New("binding element must be a struct")
+		return fmt.Errorf("binding element must be a struct, map[string]interface{} or map[string][]string, got %s", typ)
 	}
 
 	for i := 0; i < typ.NumField(); i++ {
@@ -109,64 +562,575 @@ func (b *DefaultBinder) bindData(ptr interface{}, data map[string][]string, tag
 		}
 		structFieldKind := structField.Kind()
 		inputFieldName := typeField.Tag.Get(tag)
+		if inputFieldName == "-" {
+			continue
+		}
 
 		if inputFieldName == "" {
 			inputFieldName = typeField.Name
-			// If tag is nil, we inspect if the field is a struct.
-			if _, ok := bindUnmarshaler(structField); !ok && structFieldKind == reflect.Struct {
-				if err := b.bindData(structField.Addr().Interface(), data, tag); err != nil {
+			// If tag is nil, we inspect if the field is a struct, unless it's
+			// a leaf type which is bound from a single value.
+			if !isLeafType(typeField.Type) && structFieldKind == reflect.Struct {
+				if err := b.bindValues(structField.Addr().Interface(), values, tag); err != nil {
 					return err
 				}
 				continue
 			}
+			// Header names are common words, so only the fields tagged with
+			// them are bound from headers.
+			if tag == "header" {
+				continue
+			}
 		}
 
-		inputValue, exists := data[inputFieldName]
-		if !exists {
-			// Go json.Unmarshal supports case insensitive binding.  However the
-			// url params are bound case sensitive which is inconsistent.  To
-			// fix this we must check all of the map values in a
-			// case-insensitive search.
-			inputFieldName = strings.ToLower(inputFieldName)
-			for k, v := range data {
-				if strings.ToLower(k) == inputFieldName {
-					inputValue = v
-					exists = true
-					break
+		// Uploaded files are bound separately, see `bindFiles()`.
+		if typeField.Type == fileHeaderType || typeField.Type == fileHeadersType {
+			continue
+		}
+
+		// Nested structs and slices of structs are bound from keys in dot and
+		// bracket notation, e.g. `address.city` or `items[0].name`, and maps
+		// from keys in bracket notation, e.g. `meta[key]`.
+		if ok, err := b.bindNested(structField, inputFieldName, values, tag); ok {
+			if err != nil {
+				if err = values.fail(inputFieldName, tag, err); err != nil {
+					return err
 				}
 			}
+			continue
+		}
+
+		// A tag may list several comma separated names, e.g. `form:"user_id,userId"`,
+		// which are tried in order.
+		var inputValue []string
+		exists := false
+		key := inputFieldName
+		for _, name := range strings.Split(inputFieldName, ",") {
+			key = strings.TrimSpace(name)
+			if inputValue, exists = values.lookup(key); exists {
+				break
+			}
+		}
+
+		// HTML checkboxes submit a value such as `on` when checked and nothing
+		// when unchecked, so fields tagged `type:"checkbox"` are bound leniently.
+		if structFieldKind == reflect.Bool && typeField.Tag.Get("type") == "checkbox" {
+			if exists {
+				if err := setCheckboxField(inputValue[0], structField); err != nil {
+					if err = values.fail(key, tag, err); err != nil {
+						return err
+					}
+				}
+			} else if tag == "form" {
+				structField.SetBool(false)
+			}
+			continue
 		}
 
 		if !exists {
 			continue
 		}
 
-		// Call this first, in case we're dealing with an alias to an array type
-		if ok, err := unmarshalField(typeField.Type.Kind(), inputValue[0], structField); ok {
-			if err != nil {
+		if err := setField(typeField, structField, inputValue); err != nil {
+			if err = values.fail(key, tag, err); err != nil {
 				return err
 			}
-			continue
 		}
+	}
+	return nil
+}
+
+// setField sets the struct field from its input values.
+func setField(typeField reflect.StructField, structField reflect.Value, inputValue []string) error {
+	structFieldKind := structField.Kind()
+
+	// Slices may also be sent as a single separated value, e.g.
+	// `ids=1,2,3` for a field tagged `split:","`.
+	if sep := typeField.Tag.Get("split"); sep != "" && structFieldKind == reflect.Slice {
+		if inputValue = splitValues(inputValue, sep); len(inputValue) == 0 {
+			return nil
+		}
+	}
+
+	// Values may be restricted to an allowed set with a space separated
+	// list, e.g. `oneof:"active inactive"`. Empty values are allowed.
+	if oneof := typeField.Tag.Get("oneof"); oneof != "" {
+		if v, err := validateOneOf(inputValue, strings.Fields(oneof)); err != nil {
+			return newBindingError(typeField, []string{v}, err)
+		}
+	}
+
+	// Time fields tagged with a layout, e.g. `time_format:"2006-01-02"`,
+	// are parsed with it instead of RFC 3339.
+	if layout := typeField.Tag.Get("time_format"); layout != "" {
+		if ok, err := setTimeField(inputValue, layout, typeField.Tag, structField); ok {
+			if err != nil {
+				return newBindingError(typeField, inputValue, err)
+			}
+			return nil
+		}
+	}
+
+	// Call this first, in case we're dealing with an alias to an array type
+	if ok, err := unmarshalField(typeField.Type.Kind(), inputValue[0], structField); ok {
+		if err != nil {
+			return newBindingError(typeField, inputValue[:1], err)
+		}
+		return nil
+	}
+
+	// Byte slices are decoded from a single base64 value, URL-safe if the
+	// field is tagged `encoding:"base64url"`.
+	if structFieldKind == reflect.Slice && typeField.Type.Elem().Kind() == reflect.Uint8 {
+		if err := setBytesField(inputValue[0], typeField.Tag.Get("encoding"), structField); err != nil {
+			return newBindingError(typeField, inputValue[:1], err)
+		}
+		return nil
+	}
+
+	numElems := len(inputValue)
+	if structFieldKind == reflect.Slice && numElems > 0 {
+		sliceOf := structField.Type().Elem().Kind()
+		slice := reflect.MakeSlice(structField.Type(), numElems, numElems)
+		for j := 0; j < numElems; j++ {
+			if err := setWithProperType(sliceOf, inputValue[j], slice.Index(j)); err != nil {
+				return newBindingError(typeField, inputValue, err)
+			}
+		}
+		structField.Set(slice)
+		return nil
+	}
+	if err := setWithProperType(typeField.Type.Kind(), inputValue[0], structField); err != nil {
+		return newBindingError(typeField, inputValue[:1], err)
+	}
+	return nil
+}
+
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	bindUnmarshalerType = reflect.TypeOf((*BindUnmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	fileHeaderType      = reflect.TypeOf((*multipart.FileHeader)(nil))
+	fileHeadersType     = reflect.TypeOf([]*multipart.FileHeader(nil))
+)
+
+// splitValues splits each of values on sep, dropping empty parts.
+func splitValues(values []string, sep string) []string {
+	var split []string
+	for _, v := range values {
+		for _, p := range strings.Split(v, sep) {
+			if p != "" {
+				split = append(split, p)
+			}
+		}
+	}
+	return split
+}
 
-		numElems := len(inputValue)
-		if structFieldKind == reflect.Slice && numElems > 0 {
-			sliceOf := structField.Type().Elem().Kind()
-			slice := reflect.MakeSlice(structField.Type(), numElems, numElems)
-			for j := 0; j < numElems; j++ {
-				if err := setWithProperType(sliceOf, inputValue[j], slice.Index(j)); err != nil {
+// setDefaults sets the zero valued fields of the struct ptr points to, which
+// are tagged with a default value, e.g. `default:"10"`. Slice defaults are
+// comma separated.
+func setDefaults(ptr interface{}) error {
+	typ := reflect.TypeOf(ptr).Elem()
+	if typ.Kind() != reflect.Struct {
+		return nil
+	}
+	val := reflect.ValueOf(ptr).Elem()
+	for i := 0; i < typ.NumField(); i++ {
+		typeField := typ.Field(i)
+		structField := val.Field(i)
+		if !structField.CanSet() {
+			continue
+		}
+		def, ok := typeField.Tag.Lookup("default")
+		if !ok {
+			if !isLeafType(typeField.Type) && structField.Kind() == reflect.Struct {
+				if err := setDefaults(structField.Addr().Interface()); err != nil {
 					return err
 				}
 			}
-			val.Field(i).Set(slice)
-		} else if err := setWithProperType(typeField.Type.Kind(), inputValue[0], structField); err != nil {
+			continue
+		}
+		if !reflect.DeepEqual(structField.Interface(), reflect.Zero(typeField.Type).Interface()) {
+			continue
+		}
+		if err := setDefault(def, typeField.Tag, structField); err != nil {
+			return fmt.Errorf("invalid default value for field %s: %v", typeField.Name, err)
+		}
+	}
+	return nil
+}
+
+func setDefault(def string, tag reflect.StructTag, field reflect.Value) error {
+	values := []string{def}
+	if field.Kind() == reflect.Slice && field.Type().Elem().Kind() != reflect.Uint8 {
+		values = strings.Split(def, ",")
+	}
+	if layout := tag.Get("time_format"); layout != "" {
+		if ok, err := setTimeField(values, layout, tag, field); ok {
 			return err
+		}
+	}
+	if ok, err := unmarshalField(field.Kind(), def, field); ok {
+		return err
+	}
+	if field.Kind() == reflect.Slice {
+		if field.Type().Elem().Kind() == reflect.Uint8 {
+			return setBytesField(def, tag.Get("encoding"), field)
+		}
+		slice := reflect.MakeSlice(field.Type(), len(values), len(values))
+		for j, v := range values {
+			if err := setWithProperType(field.Type().Elem().Kind(), v, slice.Index(j)); err != nil {
+				return err
+			}
+		}
+		field.Set(slice)
+		return nil
+	}
+	return setWithProperType(field.Kind(), def, field)
+}
+
+// bindFiles sets the `*multipart.FileHeader` and `[]*multipart.FileHeader`
+// fields of the struct ptr points to from uploaded files, named by their form
+// tag or field name.
+func bindFiles(ptr interface{}, files map[string][]*multipart.FileHeader) {
+	typ := reflect.TypeOf(ptr).Elem()
+	if len(files) == 0 || typ.Kind() != reflect.Struct {
+		return
+	}
+	val := reflect.ValueOf(ptr).Elem()
+	for i := 0; i < typ.NumField(); i++ {
+		typeField := typ.Field(i)
+		structField := val.Field(i)
+		if !structField.CanSet() {
+			continue
+		}
+		name := typeField.Tag.Get("form")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			if !isLeafType(typeField.Type) && structField.Kind() == reflect.Struct {
+				bindFiles(structField.Addr().Interface(), files)
+				continue
+			}
+			name = typeField.Name
+		}
+		if typeField.Type != fileHeaderType && typeField.Type != fileHeadersType {
+			continue
+		}
+		for _, n := range strings.Split(name, ",") {
+			fhs := files[strings.TrimSpace(n)]
+			if len(fhs) == 0 {
+				continue
+			}
+			if typeField.Type == fileHeaderType {
+				structField.Set(reflect.ValueOf(fhs[0]))
+			} else {
+				structField.Set(reflect.ValueOf(append([]*multipart.FileHeader(nil), fhs...)))
+			}
+			break
+		}
+	}
+}
+
+// isLeafType reports whether values of typ are bound as a whole, so struct
+// types such as time.Time are never recursed into.
+func isLeafType(typ reflect.Type) bool {
+	if typ == timeType {
+		return true
+	}
+	ptr := reflect.PtrTo(typ)
+	return ptr.Implements(bindUnmarshalerType) || ptr.Implements(textUnmarshalerType)
+}
 
+// maxBindIndex bounds the slice indexes accepted in bracket notation, and so
+// the size of the slices allocated for them.
+const maxBindIndex = 1000
+
+// bindNested binds the nested struct, struct pointer or slice of structs field
+// from the keys prefixed with any of the comma separated names. It reports
+// whether there were such keys.
+func (b *DefaultBinder) bindNested(field reflect.Value, names string, values *bindValues, tag string) (bool, error) {
+	typ := field.Type()
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		switch {
+		case isNestedStruct(typ):
+			if sub := values.sub(name + "."); sub != nil {
+				return true, b.bindValues(field.Addr().Interface(), sub, tag)
+			}
+		case typ.Kind() == reflect.Ptr && isNestedStruct(typ.Elem()):
+			if sub := values.sub(name + "."); sub != nil {
+				if field.IsNil() {
+					field.Set(reflect.New(typ.Elem()))
+				}
+				return true, b.bindValues(field.Interface(), sub, tag)
+			}
+		case typ.Kind() == reflect.Slice && isNestedStruct(typ.Elem()):
+			elems, err := values.indexed(name)
+			if err != nil || elems == nil {
+				return err != nil, err
+			}
+			n := 0
+			for i := range elems {
+				if i >= n {
+					n = i + 1
+				}
+			}
+			slice := reflect.MakeSlice(typ, n, n)
+			for i, sub := range elems {
+				if err := b.bindValues(slice.Index(i).Addr().Interface(), sub, tag); err != nil {
+					return true, err
+				}
+			}
+			field.Set(slice)
+			return true, nil
+		case isStringMap(typ):
+			m := values.keyed(name)
+			if m == nil {
+				continue
+			}
+			if field.IsNil() {
+				field.Set(reflect.MakeMap(typ))
+			}
+			for k, v := range m {
+				if typ.Elem().Kind() == reflect.String {
+					field.SetMapIndex(reflect.ValueOf(k).Convert(typ.Key()), reflect.ValueOf(v[0]).Convert(typ.Elem()))
+				} else {
+					field.SetMapIndex(reflect.ValueOf(k).Convert(typ.Key()), reflect.ValueOf(append([]string(nil), v...)).Convert(typ.Elem()))
+				}
+			}
+			return true, nil
 		}
 	}
+	return false, nil
+}
+
+// isStringMap reports whether typ is a map[string]string or map[string][]string.
+func isStringMap(typ reflect.Type) bool {
+	if typ.Kind() != reflect.Map || typ.Key().Kind() != reflect.String {
+		return false
+	}
+	elem := typ.Elem()
+	return elem.Kind() == reflect.String ||
+		elem.Kind() == reflect.Slice && elem.Elem() == reflect.TypeOf("")
+}
+
+func isNestedStruct(typ reflect.Type) bool {
+	return typ.Kind() == reflect.Struct && !isLeafType(typ)
+}
+
+// bindValues wraps the data being bound for field name lookups, keeping track
+// of the keys consumed by them.
+type bindValues struct {
+	data     map[string][]string
+	lower    map[string]string
+	consumed map[string]bool
+
+	// parent and prefix are set for the values of nested fields, whose keys
+	// are the keys of the parent without the prefix.
+	parent *bindValues
+	prefix string
+
+	// errs collects the field errors instead of failing on the first one,
+	// if set on the root values.
+	errs *FieldErrors
+}
+
+// fail records the error of binding the value of key, if errors are collected,
+// or returns it otherwise.
+func (v *bindValues) fail(key, tag string, err error) error {
+	for v.parent != nil {
+		key = v.prefix + key
+		v = v.parent
+	}
+	if v.errs == nil {
+		return err
+	}
+	*v.errs = append(*v.errs, &FieldError{Field: key, Source: tag, Message: err.Error(), Err: err})
 	return nil
 }
 
+// sub returns the values of the keys having prefix, with the prefix stripped,
+// or nil if there are none.
+func (v *bindValues) sub(prefix string) *bindValues {
+	var data map[string][]string
+	for k, values := range v.data {
+		if len(k) > len(prefix) && strings.HasPrefix(k, prefix) {
+			if data == nil {
+				data = map[string][]string{}
+			}
+			data[k[len(prefix):]] = values
+		}
+	}
+	if data == nil {
+		return nil
+	}
+	return &bindValues{data: data, parent: v, prefix: prefix}
+}
+
+// keyed returns the values of the keys in bracket notation with name, e.g.
+// `meta[key]`, by key, or nil if there are none.
+func (v *bindValues) keyed(name string) map[string][]string {
+	prefix := name + "["
+	var m map[string][]string
+	for k, values := range v.data {
+		if len(k) > len(prefix)+1 && strings.HasPrefix(k, prefix) && k[len(k)-1] == ']' {
+			key := k[len(prefix) : len(k)-1]
+			if strings.ContainsAny(key, "[]") {
+				continue
+			}
+			if m == nil {
+				m = map[string][]string{}
+			}
+			m[key] = values
+			v.consume(k)
+		}
+	}
+	return m
+}
+
+// indexed returns the values of the keys in bracket notation with name, e.g.
+// `items[0].name`, grouped by index, or nil if there are none.
+func (v *bindValues) indexed(name string) (map[int]*bindValues, error) {
+	prefix := name + "["
+	var elems map[int]*bindValues
+	for k, values := range v.data {
+		if !strings.HasPrefix(k, prefix) {
+			continue
+		}
+		end := strings.Index(k, "].")
+		if end < len(prefix) || end+2 == len(k) {
+			continue
+		}
+		index := k[len(prefix):end]
+		i, err := strconv.Atoi(index)
+		if err != nil || strconv.Itoa(i) != index {
+			continue
+		}
+		if i < 0 || i >= maxBindIndex {
+			return nil, fmt.Errorf("index %d of %s out of range", i, name)
+		}
+		if elems == nil {
+			elems = map[int]*bindValues{}
+		}
+		e, ok := elems[i]
+		if !ok {
+			e = &bindValues{data: map[string][]string{}, parent: v, prefix: k[:end+2]}
+			elems[i] = e
+		}
+		e.data[k[end+2:]] = values
+	}
+	return elems, nil
+}
+
+// lookup returns the values for name, matching the name case insensitively if
+// there is no exact match.
+func (v *bindValues) lookup(name string) ([]string, bool) {
+	if values, ok := v.data[name]; ok {
+		v.consume(name)
+		return values, true
+	}
+	// Go json.Unmarshal supports case insensitive binding.  However the
+	// url params are bound case sensitive which is inconsistent.  To
+	// fix this we must check all of the map values in a
+	// case-insensitive search, using an index of the lowercased keys
+	// which is built once.
+	if v.lower == nil {
+		v.lower = make(map[string]string, len(v.data))
+		for k := range v.data {
+			l := strings.ToLower(k)
+			if o, ok := v.lower[l]; !ok || k < o {
+				v.lower[l] = k
+			}
+		}
+	}
+	k, ok := v.lower[strings.ToLower(name)]
+	if !ok {
+		return nil, false
+	}
+	v.consume(k)
+	return v.data[k], true
+}
+
+func (v *bindValues) consume(key string) {
+	if v.consumed == nil {
+		v.consumed = map[string]bool{}
+	}
+	v.consumed[key] = true
+	if v.parent != nil {
+		v.parent.consume(v.prefix + key)
+	}
+}
+
+// unconsumed returns the sorted keys which weren't consumed by any lookup.
+func (v *bindValues) unconsumed() (keys []string) {
+	for k := range v.data {
+		if !v.consumed[k] {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return
+}
+
+// isSliceTarget reports whether ptr points to a slice or an array.
+func isSliceTarget(ptr interface{}) bool {
+	k := reflect.TypeOf(ptr).Elem().Kind()
+	return k == reflect.Slice || k == reflect.Array
+}
+
+// validateBindTarget checks that ptr is a non-nil pointer, as reflection on
+// anything else would panic while binding.
+func validateBindTarget(ptr interface{}) error {
+	if ptr == nil {
+		return errors.New("binding element must be a pointer, got nil")
+	}
+	v := reflect.ValueOf(ptr)
+	if v.Kind() != reflect.Ptr {
+		return fmt.Errorf("binding element must be a pointer, got %T", ptr)
+	}
+	if v.IsNil() {
+		return fmt.Errorf("binding element must be a non-nil pointer, got nil %T", ptr)
+	}
+	return nil
+}
+
+// validateValuesTarget checks that the non-nil pointer ptr points to a type
+// which params, query params, headers and form fields can be bound into.
+// Slices can't, but are accepted as they can be decoded from JSON and XML
+// bodies.
+func validateValuesTarget(ptr interface{}) error {
+	switch typ := reflect.TypeOf(ptr).Elem(); typ.Kind() {
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+		return nil
+	default:
+		return fmt.Errorf("binding element must be a struct, map or slice, got %s", typ)
+	}
+}
+
+// validateOneOf returns the first of values which isn't allowed, and an error
+// listing the allowed ones.
+func validateOneOf(values, allowed []string) (string, error) {
+	for _, v := range values {
+		if v == "" {
+			continue
+		}
+		valid := false
+		for _, a := range allowed {
+			if v == a {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return v, fmt.Errorf("must be one of: %s", strings.Join(allowed, ", "))
+		}
+	}
+	return "", nil
+}
+
 func setWithProperType(valueKind reflect.Kind, val string, structField reflect.Value) error {
 	// But also call it here, in case we're dealing with an array of BindUnmarshalers
 	if ok, err := unmarshalField(valueKind, val, structField); ok {
@@ -299,6 +1263,100 @@ func setBoolField(value string, field reflect.Value) error {
 	return err
 }
 
+func setCheckboxField(value string, field reflect.Value) error {
+	switch strings.ToLower(value) {
+	case "on", "yes", "checked":
+		field.SetBool(true)
+		return nil
+	case "off", "no":
+		field.SetBool(false)
+		return nil
+	}
+	return setBoolField(value, field)
+}
+
+func setBytesField(value, encoding string, field reflect.Value) error {
+	enc := base64.RawStdEncoding
+	if encoding == "base64url" {
+		enc = base64.RawURLEncoding
+	}
+	// Padding is optional
+	b, err := enc.DecodeString(strings.TrimRight(value, "="))
+	if err == nil {
+		field.SetBytes(b)
+	}
+	return err
+}
+
+// setTimeField parses values with layout into a time.Time, *time.Time or
+// []time.Time field. The layout may also be `unix` or `unixnano` for numeric
+// timestamps. Times without a zone are parsed in UTC if the `time_utc` tag is
+// set, which also converts the others to UTC, or else in the location named by
+// the `time_location` tag, defaulting to the local time zone.
+func setTimeField(values []string, layout string, tag reflect.StructTag, field reflect.Value) (bool, error) {
+	switch field.Type() {
+	case timeType:
+		t, err := parseTime(values[0], layout, tag)
+		if err == nil {
+			field.Set(reflect.ValueOf(t))
+		}
+		return true, err
+	case reflect.PtrTo(timeType):
+		t, err := parseTime(values[0], layout, tag)
+		if err == nil {
+			field.Set(reflect.ValueOf(&t))
+		}
+		return true, err
+	case reflect.SliceOf(timeType):
+		times := make([]time.Time, len(values))
+		for i, v := range values {
+			t, err := parseTime(v, layout, tag)
+			if err != nil {
+				return true, err
+			}
+			times[i] = t
+		}
+		field.Set(reflect.ValueOf(times))
+		return true, nil
+	}
+	return false, nil
+}
+
+func parseTime(value, layout string, tag reflect.StructTag) (t time.Time, err error) {
+	if value == "" {
+		return
+	}
+	utc, _ := strconv.ParseBool(tag.Get("time_utc"))
+	switch layout {
+	case "unix", "unixnano":
+		var n int64
+		if n, err = strconv.ParseInt(value, 10, 64); err != nil {
+			return
+		}
+		if layout == "unix" {
+			t = time.Unix(n, 0)
+		} else {
+			t = time.Unix(0, n)
+		}
+	default:
+		loc := time.Local
+		if utc {
+			loc = time.UTC
+		} else if name := tag.Get("time_location"); name != "" {
+			if loc, err = time.LoadLocation(name); err != nil {
+				return
+			}
+		}
+		if t, err = time.ParseInLocation(layout, value, loc); err != nil {
+			return
+		}
+	}
+	if utc {
+		t = t.UTC()
+	}
+	return
+}
+
 func setFloatField(value string, bitSize int, field reflect.Value) error {
 	if value == "" {
 		value = "0.0"
@@ -309,3 +1367,26 @@ func setFloatField(value string, bitSize int, field reflect.Value) error {
 	}
 	return err
 }
+
+// limitedBody is a request body failing reads past the binder body limit.
+type limitedBody struct {
+	io.ReadCloser
+	remaining int64
+	exceeded  bool
+}
+
+func (l *limitedBody) Read(p []byte) (n int, err error) {
+	if l.exceeded {
+		return 0, ErrStatusRequestEntityTooLarge
+	}
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1]
+	}
+	n, err = l.ReadCloser.Read(p)
+	if int64(n) > l.remaining {
+		l.exceeded = true
+		return 0, ErrStatusRequestEntityTooLarge
+	}
+	l.remaining -= int64(n)
+	return
+}
@@ -5,7 +5,9 @@ import (
 	"encoding/json"
 	"encoding/xml"
 	"errors"
+	"fmt"
 	"io"
+	"io/ioutil"
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
@@ -59,6 +61,7 @@ type (
 	Timestamp   time.Time
 	TA          []Timestamp
 	StringArray []string
+	CustomID    string
 	Struct      struct {
 		Foo string
 	}
@@ -75,6 +78,11 @@ func (a *StringArray) UnmarshalParam(src string) error {
 	return nil
 }
 
+func (id *CustomID) UnmarshalParam(src string) error {
+	*id = CustomID(strings.ToUpper(src))
+	return nil
+}
+
 func (s *Struct) UnmarshalParam(src string) error {
 	*s = Struct{
 		Foo: src,
@@ -195,6 +203,22 @@ func TestBindQueryParamsCaseSensitivePrioritized(t *testing.T) {
 	}
 }
 
+func TestBindFormCaseInsensitive(t *testing.T) {
+	type request struct {
+		Email string `form:"email"`
+	}
+	for _, key := range []string{"email", "Email", "EMAIL"} {
+		e := New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(key+"=jon@labstack.com"))
+		req.Header.Set(HeaderContentType, MIMEApplicationForm)
+		c := e.NewContext(req, httptest.NewRecorder())
+		r := new(request)
+		if assert.NoError(t, c.Bind(r), key) {
+			assert.Equal(t, "jon@labstack.com", r.Email, key)
+		}
+	}
+}
+
 func TestBindUnmarshalParam(t *testing.T) {
 	e := New()
 	req := httptest.NewRequest(http.MethodGet, "/?ts=2016-12-06T19:09:05Z&sa=one,two,three&ta=2016-12-06T19:09:05Z&ta=2016-12-06T19:09:05Z&ST=baz", nil)
@@ -219,6 +243,50 @@ func TestBindUnmarshalParam(t *testing.T) {
 	}
 }
 
+func TestBindUnmarshalParamSlice(t *testing.T) {
+	e := New()
+	req := httptest.NewRequest(http.MethodGet, "/?id=a1&id=b2", nil)
+	c := e.NewContext(req, httptest.NewRecorder())
+	result := struct {
+		IDs    []CustomID  `query:"id"`
+		IDPtrs []*CustomID `query:"id"`
+	}{}
+	if assert.NoError(t, c.Bind(&result)) {
+		assert.Equal(t, []CustomID{"A1", "B2"}, result.IDs)
+		if assert.Len(t, result.IDPtrs, 2) {
+			assert.Equal(t, CustomID("A1"), *result.IDPtrs[0])
+			assert.Equal(t, CustomID("B2"), *result.IDPtrs[1])
+		}
+	}
+}
+
+func TestBindSplit(t *testing.T) {
+	e := New()
+	req := httptest.NewRequest(http.MethodGet, "/?ids=1,2,3&ids=4&tags=a|b&empty=,&names=x,y", nil)
+	c := e.NewContext(req, httptest.NewRecorder())
+	result := struct {
+		IDs   []int    `query:"ids" split:","`
+		Tags  []string `query:"tags" split:"|"`
+		Empty []int    `query:"empty" split:","`
+		Names []string `query:"names"`
+	}{}
+	if assert.NoError(t, c.Bind(&result)) {
+		assert.Equal(t, []int{1, 2, 3, 4}, result.IDs)
+		assert.Equal(t, []string{"a", "b"}, result.Tags)
+		assert.Nil(t, result.Empty)
+		assert.Equal(t, []string{"x,y"}, result.Names)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/?ids=1,x", nil)
+	c = e.NewContext(req, httptest.NewRecorder())
+	err := c.Bind(&struct {
+		IDs []int `query:"ids" split:","`
+	}{})
+	if assert.IsType(t, new(HTTPError), err) {
+		assert.Equal(t, http.StatusBadRequest, err.(*HTTPError).Code)
+	}
+}
+
 func TestBindUnmarshalText(t *testing.T) {
 	e := New()
 	req := httptest.NewRequest(GET, "/?ts=2016-12-06T19:09:05Z&sa=one,two,three&ta=2016-12-06T19:09:05Z&ta=2016-12-06T19:09:05Z&ST=baz", nil)
@@ -280,6 +348,42 @@ func TestBindMultipartForm(t *testing.T) {
 	testBindOkay(assert, body, mw.FormDataContentType())
 }
 
+func TestBindMultipartFiles(t *testing.T) {
+	body := new(bytes.Buffer)
+	mw := multipart.NewWriter(body)
+	mw.WriteField("name", "Jon Snow")
+	fw, _ := mw.CreateFormFile("avatar", "avatar.png")
+	fw.Write([]byte("png"))
+	fw, _ = mw.CreateFormFile("docs", "a.txt")
+	fw.Write([]byte("a"))
+	fw, _ = mw.CreateFormFile("docs", "b.txt")
+	fw.Write([]byte("b"))
+	mw.Close()
+
+	e := New()
+	req := httptest.NewRequest(http.MethodPost, "/", body)
+	req.Header.Set(HeaderContentType, mw.FormDataContentType())
+	c := e.NewContext(req, httptest.NewRecorder())
+	u := struct {
+		Name    string                  `form:"name"`
+		Avatar  *multipart.FileHeader   `form:"avatar"`
+		Docs    []*multipart.FileHeader `form:"docs"`
+		Missing *multipart.FileHeader   `form:"missing"`
+	}{}
+	if assert.NoError(t, c.Bind(&u)) {
+		assert.Equal(t, "Jon Snow", u.Name)
+		if assert.NotNil(t, u.Avatar) {
+			assert.Equal(t, "avatar.png", u.Avatar.Filename)
+			assert.Equal(t, int64(3), u.Avatar.Size)
+		}
+		if assert.Len(t, u.Docs, 2) {
+			assert.Equal(t, "a.txt", u.Docs[0].Filename)
+			assert.Equal(t, "b.txt", u.Docs[1].Filename)
+		}
+		assert.Nil(t, u.Missing)
+	}
+}
+
 func TestBindUnsupportedMediaType(t *testing.T) {
 	assert := assert.New(t)
 	testBindError(assert, strings.NewReader(invalidContent), MIMEApplicationJSON, &json.SyntaxError{})
@@ -293,6 +397,670 @@ func TestBindbindData(t *testing.T) {
 	assertBindTestStruct(assert, ts)
 }
 
+func TestBindMap(t *testing.T) {
+	assert := assert.New(t)
+	ts := new(bindTestStruct)
+	if assert.NoError(BindMap(ts, values)) {
+		assertBindTestStruct(assert, ts)
+	}
+
+	type config struct {
+		Addr    string   `form:"addr"`
+		Hosts   []string `form:"host"`
+		Ports   []int    `form:"port"`
+		Timeout *int     `form:"timeout"`
+		Debug   *bool
+	}
+	cfg := new(config)
+	err := BindMap(cfg, map[string][]string{
+		"addr":    {":8080"},
+		"host":    {"a.example.com", "b.example.com"},
+		"port":    {"80", "443"},
+		"timeout": {"30"},
+		"DEBUG":   {"true"},
+	})
+	if assert.NoError(err) {
+		assert.Equal(":8080", cfg.Addr)
+		assert.Equal([]string{"a.example.com", "b.example.com"}, cfg.Hosts)
+		assert.Equal([]int{80, 443}, cfg.Ports)
+		if assert.NotNil(cfg.Timeout) {
+			assert.Equal(30, *cfg.Timeout)
+		}
+		if assert.NotNil(cfg.Debug) {
+			assert.True(*cfg.Debug)
+		}
+	}
+
+	// Conversion error
+	cfg = new(config)
+	assert.Error(BindMap(cfg, map[string][]string{"port": {"http"}}))
+
+	// Defaults
+	type paging struct {
+		Page    int `form:"page" default:"1"`
+		PerPage int `form:"per_page" default:"20"`
+	}
+	p := new(paging)
+	if assert.NoError(BindMap(p, map[string][]string{"page": {"3"}})) {
+		assert.Equal(3, p.Page)
+		assert.Equal(20, p.PerPage)
+	}
+
+	// Non-struct target
+	assert.Error(BindMap(new(string), map[string][]string{"addr": {":8080"}}))
+}
+
+func TestBindInvalidTarget(t *testing.T) {
+	e := New()
+	req := httptest.NewRequest(http.MethodPost, "/?id=1", strings.NewReader(userJSON))
+	req.Header.Set(HeaderContentType, MIMEApplicationJSON)
+	c := e.NewContext(req, httptest.NewRecorder())
+
+	assert := assert.New(t)
+
+	// nil
+	err := c.Bind(nil)
+	if assert.IsType(new(HTTPError), err) {
+		assert.Equal(http.StatusInternalServerError, err.(*HTTPError).Code)
+		assert.EqualError(err.(*HTTPError).Internal, "binding element must be a pointer, got nil")
+	}
+
+	// Non-pointer
+	err = c.Bind(user{})
+	if assert.IsType(new(HTTPError), err) {
+		assert.Equal(http.StatusInternalServerError, err.(*HTTPError).Code)
+		assert.EqualError(err.(*HTTPError).Internal, "binding element must be a pointer, got echo.user")
+	}
+
+	// nil pointer
+	var u *user
+	err = c.Bind(u)
+	if assert.IsType(new(HTTPError), err) {
+		assert.Equal(http.StatusInternalServerError, err.(*HTTPError).Code)
+		assert.EqualError(err.(*HTTPError).Internal, "binding element must be a non-nil pointer, got nil *echo.user")
+	}
+
+	// Pointer to non-struct, decoded from the body only
+	bind := func(i interface{}, body string) error {
+		req := httptest.NewRequest(http.MethodPost, "/?id=1", strings.NewReader(body))
+		req.Header.Set(HeaderContentType, MIMEApplicationJSON)
+		return e.NewContext(req, httptest.NewRecorder()).Bind(i)
+	}
+	var v interface{}
+	if assert.NoError(bind(&v, `{"a":1}`)) {
+		assert.Equal(map[string]interface{}{"a": float64(1)}, v)
+	}
+	var str string
+	if assert.NoError(bind(&str, `"test"`)) {
+		assert.Equal("test", str)
+	}
+	n := new(int)
+	if assert.NoError(bind(n, `5`)) {
+		assert.Equal(5, *n)
+	}
+	err = bind(n, userJSON)
+	if assert.IsType(new(HTTPError), err) {
+		assert.Equal(http.StatusBadRequest, err.(*HTTPError).Code)
+	}
+
+	// Pointer to non-struct, without any data to bind
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	c = e.NewContext(req, httptest.NewRecorder())
+	err = c.Bind(new(int))
+	if assert.IsType(new(HTTPError), err) {
+		assert.Equal(http.StatusInternalServerError, err.(*HTTPError).Code)
+		assert.EqualError(err.(*HTTPError).Internal, "binding element must be a struct, map or slice, got int")
+	}
+	err = c.BindQueryParams(new(int))
+	if assert.IsType(new(HTTPError), err) {
+		assert.Equal(http.StatusInternalServerError, err.(*HTTPError).Code)
+	}
+	assert.EqualError(BindMap(new(int), nil), "binding element must be a struct, map or slice, got int")
+
+	// nil map
+	m := map[string]interface{}(nil)
+	if assert.NoError(BindMap(&m, map[string][]string{"id": {"1"}})) {
+		assert.Equal(map[string]interface{}{"id": "1"}, m)
+	}
+}
+
+func TestBindTagAliases(t *testing.T) {
+	type request struct {
+		UserID int    `query:"user_id,userId"`
+		Name   string `query:"name"`
+	}
+	assert := assert.New(t)
+	bind := func(target string) *request {
+		e := New()
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		c := e.NewContext(req, httptest.NewRecorder())
+		r := new(request)
+		assert.NoError(c.Bind(r))
+		return r
+	}
+
+	assert.Equal(1, bind("/?user_id=1").UserID)
+	assert.Equal(2, bind("/?userId=2").UserID)
+	assert.Equal(1, bind("/?userId=2&user_id=1").UserID)
+	assert.Equal(3, bind("/?USERID=3&name=Jon").UserID)
+	assert.Equal(0, bind("/?name=Jon").UserID)
+}
+
+func TestBindLeafTypes(t *testing.T) {
+	type request struct {
+		Created time.Time `form:"-"`
+		Updated time.Time
+		Name    string `form:"-"`
+		Nested  struct {
+			Title string `form:"title"`
+		}
+	}
+	r := new(request)
+	err := BindMap(r, map[string][]string{
+		"Created": {"2016-12-06T19:09:05Z"},
+		"Updated": {"2016-12-06T19:09:05Z"},
+		"Name":    {"Jon"},
+		"-":       {"Jon"},
+		"title":   {"Mr"},
+	})
+	if assert.NoError(t, err) {
+		assert.True(t, r.Created.IsZero())
+		assert.Equal(t, time.Date(2016, 12, 6, 19, 9, 5, 0, time.UTC), r.Updated)
+		assert.Empty(t, r.Name)
+		assert.Equal(t, "Mr", r.Nested.Title)
+	}
+
+	assert.True(t, isLeafType(reflect.TypeOf(time.Time{})))
+	assert.True(t, isLeafType(reflect.TypeOf(Timestamp{})))
+	assert.True(t, isLeafType(reflect.TypeOf(Struct{})))
+	assert.False(t, isLeafType(reflect.TypeOf(struct{ Title string }{})))
+}
+
+func TestBindUnconsumed(t *testing.T) {
+	type request struct {
+		ID     int    `query:"id"`
+		Name   string `form:"name"`
+		Nested struct {
+			Title string `form:"title"`
+		}
+	}
+	e := New()
+	req := httptest.NewRequest(http.MethodPost, "/?id=1&debug=true", strings.NewReader("name=Jon&TITLE=Mr&role=admin"))
+	req.Header.Set(HeaderContentType, MIMEApplicationForm)
+	c := e.NewContext(req, httptest.NewRecorder())
+	r := new(request)
+	unconsumed, err := new(DefaultBinder).BindUnconsumed(r, c)
+	if assert.NoError(t, err) {
+		assert.Equal(t, 1, r.ID)
+		assert.Equal(t, "Jon", r.Name)
+		assert.Equal(t, "Mr", r.Nested.Title)
+		assert.Equal(t, []string{"debug", "role"}, unconsumed)
+	}
+
+	// Maps consume everything
+	req = httptest.NewRequest(http.MethodGet, "/?a=1&b=2", nil)
+	c = e.NewContext(req, httptest.NewRecorder())
+	m := map[string][]string{}
+	unconsumed, err = new(DefaultBinder).BindUnconsumed(&m, c)
+	if assert.NoError(t, err) {
+		assert.Empty(t, unconsumed)
+	}
+}
+
+func TestBindSlice(t *testing.T) {
+	type item struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+	e := New()
+
+	// JSON array body, query params are ignored
+	req := httptest.NewRequest(http.MethodPost, "/?page=1", strings.NewReader(`[{"id":1,"name":"Jon"},{"id":2,"name":"Joe"}]`))
+	req.Header.Set(HeaderContentType, MIMEApplicationJSON)
+	c := e.NewContext(req, httptest.NewRecorder())
+	items := []item{}
+	if assert.NoError(t, c.Bind(&items)) {
+		assert.Equal(t, []item{{1, "Jon"}, {2, "Joe"}}, items)
+	}
+
+	// Query params
+	req = httptest.NewRequest(http.MethodGet, "/?id=1", nil)
+	c = e.NewContext(req, httptest.NewRecorder())
+	err := c.Bind(&items)
+	if assert.IsType(t, new(HTTPError), err) {
+		assert.Equal(t, http.StatusBadRequest, err.(*HTTPError).Code)
+		assert.Equal(t, "query data can't be bound into []echo.item, binding element must be a struct or map", err.(*HTTPError).Message)
+	}
+
+	// Headers and path params are ignored
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(HeaderAccept, MIMEApplicationJSON)
+	c = e.NewContext(req, httptest.NewRecorder())
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+	items = []item{}
+	if assert.NoError(t, c.Bind(&items)) {
+		assert.Empty(t, items)
+	}
+
+	// Form body
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader("id=1"))
+	req.Header.Set(HeaderContentType, MIMEApplicationForm)
+	c = e.NewContext(req, httptest.NewRecorder())
+	err = c.Bind(&items)
+	if assert.IsType(t, new(HTTPError), err) {
+		assert.Equal(t, "form data can't be bound into []echo.item, binding element must be a struct or map", err.(*HTTPError).Message)
+	}
+}
+
+func TestBindBytes(t *testing.T) {
+	type request struct {
+		Signature []byte `form:"sig"`
+		Token     []byte `form:"token" encoding:"base64url"`
+	}
+	raw := []byte{0xfb, 0xff, 0xfe, 's', 'i', 'g'}
+
+	r := new(request)
+	err := BindMap(r, map[string][]string{
+		"sig":   {"+//+c2ln"},
+		"token": {"-__-c2ln"},
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, raw, r.Signature)
+		assert.Equal(t, raw, r.Token)
+	}
+
+	// Padding is optional
+	r = new(request)
+	if assert.NoError(t, BindMap(r, map[string][]string{"sig": {"c2lnbg=="}, "token": {"c2lnbg"}})) {
+		assert.Equal(t, []byte("sign"), r.Signature)
+		assert.Equal(t, []byte("sign"), r.Token)
+	}
+
+	// Invalid
+	e := New()
+	req := httptest.NewRequest(http.MethodGet, "/?token=%2B%2F%2F%2Bc2ln", nil)
+	c := e.NewContext(req, httptest.NewRecorder())
+	err = c.Bind(new(request))
+	if assert.IsType(t, new(HTTPError), err) {
+		assert.Equal(t, http.StatusBadRequest, err.(*HTTPError).Code)
+		assert.Contains(t, err.(*HTTPError).Message, `invalid value "+//+c2ln" for field Token, expected []uint8`)
+		var be *BindingError
+		assert.True(t, errors.As(err, &be))
+	}
+}
+
+func TestBindOneOf(t *testing.T) {
+	type request struct {
+		Status string   `query:"status" oneof:"active inactive banned"`
+		Tags   []string `query:"tag" oneof:"a b"`
+	}
+	e := New()
+	bind := func(target string) (*request, error) {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		c := e.NewContext(req, httptest.NewRecorder())
+		r := new(request)
+		return r, c.Bind(r)
+	}
+
+	// Valid
+	r, err := bind("/?status=inactive&tag=a&tag=b")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "inactive", r.Status)
+		assert.Equal(t, []string{"a", "b"}, r.Tags)
+	}
+
+	// Invalid
+	_, err = bind("/?status=deleted")
+	if assert.IsType(t, new(HTTPError), err) {
+		assert.Equal(t, http.StatusBadRequest, err.(*HTTPError).Code)
+		assert.Equal(t, `invalid value "deleted" for field Status, expected string: must be one of: active, inactive, banned`, err.(*HTTPError).Message)
+		var be *BindingError
+		if assert.True(t, errors.As(err, &be)) {
+			assert.Equal(t, []string{"deleted"}, be.Values)
+		}
+	}
+	_, err = bind("/?tag=a&tag=c")
+	assert.Error(t, err)
+
+	// Empty optional value
+	r, err = bind("/?status=")
+	if assert.NoError(t, err) {
+		assert.Empty(t, r.Status)
+	}
+}
+
+func TestBindHeader(t *testing.T) {
+	type request struct {
+		RequestID     string `header:"X-Request-ID"`
+		Authorization string `header:"authorization"`
+		Retries       int    `header:"X-Retries"`
+		Accept        string
+		ID            int `query:"id"`
+	}
+	e := New()
+	req := httptest.NewRequest(http.MethodGet, "/?id=1", nil)
+	req.Header.Set(HeaderXRequestID, "abc")
+	req.Header.Set(HeaderAuthorization, "Bearer token")
+	req.Header.Set("X-Retries", "3")
+	req.Header.Set(HeaderAccept, MIMEApplicationJSON)
+	c := e.NewContext(req, httptest.NewRecorder())
+	r := new(request)
+	if assert.NoError(t, c.Bind(r)) {
+		assert.Equal(t, "abc", r.RequestID)
+		assert.Equal(t, "Bearer token", r.Authorization)
+		assert.Equal(t, 3, r.Retries)
+		assert.Empty(t, r.Accept)
+		assert.Equal(t, 1, r.ID)
+	}
+
+	req.Header.Set("X-Retries", "many")
+	c = e.NewContext(req, httptest.NewRecorder())
+	err := c.Bind(new(request))
+	if assert.IsType(t, new(HTTPError), err) {
+		assert.Equal(t, http.StatusBadRequest, err.(*HTTPError).Code)
+	}
+
+	// Maps don't get headers
+	req = httptest.NewRequest(http.MethodPost, "/?q=1", strings.NewReader(`{"a":"b"}`))
+	req.Header.Set(HeaderContentType, MIMEApplicationJSON)
+	req.Header.Set("User-Agent", "x")
+	c = e.NewContext(req, httptest.NewRecorder())
+	m := map[string]interface{}{}
+	if assert.NoError(t, c.Bind(&m)) {
+		assert.Equal(t, map[string]interface{}{"a": "b", "q": "1"}, m)
+	}
+	values := map[string][]string{}
+	if assert.NoError(t, new(DefaultBinder).BindHeaders(&values, c)) {
+		assert.Empty(t, values)
+	}
+}
+
+func TestBindTimeFormat(t *testing.T) {
+	type request struct {
+		Date     time.Time   `form:"date" time_format:"2006-01-02" time_utc:"true"`
+		Local    time.Time   `form:"local" time_format:"2006-01-02 15:04" time_location:"America/New_York"`
+		Unix     *time.Time  `form:"unix" time_format:"unix"`
+		Dates    []time.Time `form:"dates" time_format:"02.01.2006" time_utc:"1"`
+		Default  time.Time   `form:"default"`
+		Optional time.Time   `form:"optional" time_format:"2006-01-02"`
+	}
+	r := new(request)
+	err := BindMap(r, map[string][]string{
+		"date":     {"2019-12-06"},
+		"local":    {"2019-12-06 19:09"},
+		"unix":     {"1575659345"},
+		"dates":    {"06.12.2019", "07.12.2019"},
+		"default":  {"2019-12-06T19:09:05Z"},
+		"optional": {""},
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, time.Date(2019, 12, 6, 0, 0, 0, 0, time.UTC), r.Date)
+		ny, _ := time.LoadLocation("America/New_York")
+		assert.Equal(t, time.Date(2019, 12, 6, 19, 9, 0, 0, ny), r.Local)
+		assert.Equal(t, "2019-12-07 00:09:00 +0000 UTC", r.Local.UTC().String())
+		if assert.NotNil(t, r.Unix) {
+			assert.Equal(t, int64(1575659345), r.Unix.Unix())
+		}
+		assert.Equal(t, []time.Time{
+			time.Date(2019, 12, 6, 0, 0, 0, 0, time.UTC),
+			time.Date(2019, 12, 7, 0, 0, 0, 0, time.UTC),
+		}, r.Dates)
+		assert.Equal(t, time.Date(2019, 12, 6, 19, 9, 5, 0, time.UTC), r.Default)
+		assert.True(t, r.Optional.IsZero())
+	}
+
+	err = BindMap(new(request), map[string][]string{"date": {"06.12.2019"}})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), `invalid value "06.12.2019" for field Date`)
+		var be *BindingError
+		if assert.True(t, errors.As(err, &be)) {
+			assert.IsType(t, new(time.ParseError), be.Err)
+		}
+	}
+}
+
+func TestBindNested(t *testing.T) {
+	type (
+		address struct {
+			City    string `form:"city"`
+			Country string `form:"country"`
+		}
+		item struct {
+			Name string `form:"name"`
+			Qty  int    `form:"qty"`
+			Tags []string
+		}
+		request struct {
+			Name     string   `form:"name"`
+			Address  address  `form:"address"`
+			Billing  *address `form:"billing"`
+			Shipping *address `form:"shipping"`
+			Items    []item   `form:"items"`
+		}
+	)
+	data := map[string][]string{
+		"name":            {"Jon"},
+		"address.city":    {"Berlin"},
+		"address.country": {"DE"},
+		"billing.city":    {"Paris"},
+		"items[0].name":   {"apple"},
+		"items[0].qty":    {"2"},
+		"items[2].name":   {"pear"},
+		"items[2].tags":   {"green", "ripe"},
+		"items[x].name":   {"invalid"},
+		"extra":           {"1"},
+	}
+	r := new(request)
+	if assert.NoError(t, BindMap(r, data)) {
+		assert.Equal(t, "Jon", r.Name)
+		assert.Equal(t, address{"Berlin", "DE"}, r.Address)
+		if assert.NotNil(t, r.Billing) {
+			assert.Equal(t, address{City: "Paris"}, *r.Billing)
+		}
+		assert.Nil(t, r.Shipping)
+		assert.Equal(t, []item{
+			{Name: "apple", Qty: 2},
+			{},
+			{Name: "pear", Tags: []string{"green", "ripe"}},
+		}, r.Items)
+	}
+
+	// Unconsumed keys are reported with their full name
+	e := New()
+	req := httptest.NewRequest(http.MethodGet, "/?address.city=Berlin&address.zip=10115&items[0].name=apple&items[0].color=red", nil)
+	c := e.NewContext(req, httptest.NewRecorder())
+	unconsumed, err := new(DefaultBinder).BindUnconsumed(new(struct {
+		Address address `query:"address"`
+		Items   []item  `query:"items"`
+	}), c)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"address.zip", "items[0].color"}, unconsumed)
+	}
+
+	err = BindMap(new(request), map[string][]string{"items[5000].name": {"apple"}})
+	assert.EqualError(t, err, "index 5000 of items out of range")
+}
+
+func TestBindMapFields(t *testing.T) {
+	type labels map[string]string
+	type request struct {
+		Meta    map[string]string   `form:"meta"`
+		Filters map[string][]string `form:"filter"`
+		Labels  labels              `form:"labels"`
+		Empty   map[string]string   `form:"empty"`
+	}
+	r := new(request)
+	err := BindMap(r, map[string][]string{
+		"meta[source]":   {"web"},
+		"meta[campaign]": {"spring", "ignored"},
+		"filter[status]": {"active", "pending"},
+		"labels[env]":    {"prod"},
+		"meta[a][b]":     {"nested"},
+		"meta[]":         {"no key"},
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, map[string]string{"source": "web", "campaign": "spring"}, r.Meta)
+		assert.Equal(t, map[string][]string{"status": {"active", "pending"}}, r.Filters)
+		assert.Equal(t, labels{"env": "prod"}, r.Labels)
+		assert.Nil(t, r.Empty)
+	}
+}
+
+func TestBindRegister(t *testing.T) {
+	// A decoder for "key=value" lines
+	decode := func(r io.Reader, i interface{}) error {
+		b, err := ioutil.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		data := map[string][]string{}
+		for _, line := range strings.Split(strings.TrimSpace(string(b)), "\n") {
+			kv := strings.SplitN(line, "=", 2)
+			if len(kv) != 2 {
+				return fmt.Errorf("invalid line %q", line)
+			}
+			data[kv[0]] = []string{kv[1]}
+		}
+		return BindMap(i, data)
+	}
+	e := New()
+	e.Binder.(*DefaultBinder).Register("text/x-properties", decode)
+	bind := func(ctype, body string) (*user, error) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set(HeaderContentType, ctype)
+		c := e.NewContext(req, httptest.NewRecorder())
+		u := new(user)
+		return u, c.Bind(u)
+	}
+
+	u, err := bind("text/x-properties; charset=UTF-8", "id=1\nname=Jon Snow\n")
+	if assert.NoError(t, err) {
+		assert.Equal(t, &user{ID: 1, Name: "Jon Snow"}, u)
+	}
+
+	_, err = bind("text/x-properties", "invalid")
+	if assert.IsType(t, new(HTTPError), err) {
+		assert.Equal(t, http.StatusBadRequest, err.(*HTTPError).Code)
+		assert.Equal(t, `invalid line "invalid"`, err.(*HTTPError).Message)
+	}
+
+	// Built-in decoders are still used
+	u, err = bind(MIMEApplicationJSON, userJSON)
+	if assert.NoError(t, err) {
+		assert.Equal(t, &user{ID: 1, Name: "Jon Snow"}, u)
+	}
+	_, err = bind("text/x-unknown", "id=1")
+	assert.Equal(t, ErrUnsupportedMediaType, err)
+}
+
+func TestBindCheckbox(t *testing.T) {
+	type request struct {
+		Agree bool   `form:"agree" type:"checkbox"`
+		Name  string `form:"name"`
+	}
+	assert := assert.New(t)
+	bind := func(form string, r *request) error {
+		e := New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(form))
+		req.Header.Set(HeaderContentType, MIMEApplicationForm)
+		c := e.NewContext(req, httptest.NewRecorder())
+		return c.Bind(r)
+	}
+
+	for _, v := range []string{"on", "ON", "yes", "Yes", "1", "checked", "true", "TRUE"} {
+		r := new(request)
+		if assert.NoError(bind("agree="+v, r), v) {
+			assert.True(r.Agree, v)
+		}
+	}
+	for _, v := range []string{"off", "no", "0", "false", ""} {
+		r := &request{Agree: true}
+		if assert.NoError(bind("agree="+v, r), v) {
+			assert.False(r.Agree, v)
+		}
+	}
+
+	// Unchecked
+	r := &request{Agree: true}
+	if assert.NoError(bind("name=Jon", r)) {
+		assert.False(r.Agree)
+		assert.Equal("Jon", r.Name)
+	}
+
+	// Invalid
+	assert.Error(bind("agree=maybe", new(request)))
+}
+
+type csvBinder struct{}
+
+func (csvBinder) Bind(i interface{}, c Context) error {
+	if c.Request().Header.Get(HeaderContentType) != "text/csv" {
+		return new(DefaultBinder).Bind(i, c)
+	}
+	b, err := ioutil.ReadAll(c.Request().Body)
+	if err != nil {
+		return err
+	}
+	fields := strings.Split(strings.TrimSpace(string(b)), ",")
+	u := i.(*user)
+	u.ID, _ = strconv.Atoi(fields[0])
+	u.Name = fields[1]
+	return nil
+}
+
+func TestBindWithBinder(t *testing.T) {
+	e := New()
+	h := func(c Context) error {
+		u := new(user)
+		if err := c.Bind(u); err != nil {
+			return err
+		}
+		return c.JSON(http.StatusOK, u)
+	}
+	type wrappedContext struct {
+		Context
+	}
+	e.POST("/csv", h, WithBinder(csvBinder{}))
+	e.POST("/default", h)
+	e.POST("/wrapped", func(c Context) error {
+		// The route binder isn't a value of the store
+		if len(c.Keys()) > 0 {
+			return errors.New("unexpected keys")
+		}
+		c.ResetStore()
+		return h(c)
+	}, func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			return next(wrappedContext{c})
+		}
+	}, WithBinder(csvBinder{}))
+	post := func(path, ctype, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set(HeaderContentType, ctype)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Route binder
+	rec := post("/csv", "text/csv", "1,Jon Snow")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, userJSON+"\n", rec.Body.String())
+
+	rec = post("/csv", MIMEApplicationJSON, userJSON)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, userJSON+"\n", rec.Body.String())
+
+	rec = post("/wrapped", "text/csv", "1,Jon Snow")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, userJSON+"\n", rec.Body.String())
+
+	// Global binder
+	rec = post("/default", "text/csv", "1,Jon Snow")
+	assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+}
+
 func TestBindParam(t *testing.T) {
 	e := New()
 	req := httptest.NewRequest(GET, "/", nil)
@@ -360,6 +1128,248 @@ func TestBindUnmarshalTypeError(t *testing.T) {
 	assert.Equal(t, he, err)
 }
 
+func TestBindUnmarshalTypeErrors(t *testing.T) {
+	type request struct {
+		ID     int     `json:"id"`
+		Name   string  `json:"name"`
+		Age    int     `json:"age"`
+		Active bool    `json:"active"`
+		Score  float64 `json:"score"`
+	}
+	body := bytes.NewBufferString(`{"id": "text", "name": "Jon", "age": "old", "active": 1, "score": 9.5}`)
+	e := New()
+	req := httptest.NewRequest(http.MethodPost, "/", body)
+	req.Header.Set(HeaderContentType, MIMEApplicationJSON)
+	c := e.NewContext(req, httptest.NewRecorder())
+	r := new(request)
+
+	err := c.Bind(r)
+	if assert.IsType(t, new(HTTPError), err) {
+		he := err.(*HTTPError)
+		assert.Equal(t, http.StatusBadRequest, he.Code)
+		assert.Equal(t, "Unmarshal type errors: expected=bool, got=number, field=active; "+
+			"expected=int, got=string, field=age; expected=int, got=string, field=id", he.Message)
+		if assert.IsType(t, new(BindError), he.Internal) {
+			assert.Len(t, he.Internal.(*BindError).Errors, 3)
+		}
+	}
+	// Valid fields are still bound
+	assert.Equal(t, "Jon", r.Name)
+	assert.Equal(t, 9.5, r.Score)
+}
+
+func TestBindStrictJSON(t *testing.T) {
+	e := New()
+	e.Binder = &DefaultBinder{StrictJSON: true}
+	bind := func(body string) error {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set(HeaderContentType, MIMEApplicationJSON)
+		c := e.NewContext(req, httptest.NewRecorder())
+		return c.Bind(new(user))
+	}
+
+	assert.NoError(t, bind(userJSON))
+
+	err := bind(`{"id":1,"nmae":"Jon Snow"}`)
+	if assert.IsType(t, new(HTTPError), err) {
+		assert.Equal(t, http.StatusBadRequest, err.(*HTTPError).Code)
+		assert.Equal(t, `Unknown field error: field="nmae"`, err.(*HTTPError).Message)
+	}
+
+	err = bind(`{"id":"1","name":"Jon Snow"}`)
+	if assert.IsType(t, new(HTTPError), err) {
+		assert.Equal(t, "Unmarshal type error: expected=int, got=string, field=id, offset=9", err.(*HTTPError).Message)
+	}
+}
+
+func TestBindBodyLimit(t *testing.T) {
+	e := New()
+	e.Binder = &DefaultBinder{BodyLimit: "20B"}
+	bind := func(body io.Reader, ctype string) error {
+		req := httptest.NewRequest(http.MethodPost, "/", body)
+		req.Header.Set(HeaderContentType, ctype)
+		c := e.NewContext(req, httptest.NewRecorder())
+		return c.Bind(new(user))
+	}
+
+	assert.NoError(t, bind(strings.NewReader(`{"id":1}`), MIMEApplicationJSON))
+
+	// Known size
+	err := bind(strings.NewReader(userJSON), MIMEApplicationJSON)
+	assert.Equal(t, ErrStatusRequestEntityTooLarge, err)
+
+	// Unknown size
+	err = bind(ioutil.NopCloser(strings.NewReader(userJSON)), MIMEApplicationJSON)
+	assert.Equal(t, ErrStatusRequestEntityTooLarge, err)
+
+	err = bind(ioutil.NopCloser(strings.NewReader(userXML)), MIMEApplicationXML)
+	assert.Equal(t, ErrStatusRequestEntityTooLarge, err)
+
+	err = bind(ioutil.NopCloser(strings.NewReader("id=1&name=Jon+Snow+Targaryen")), MIMEApplicationForm)
+	assert.Equal(t, ErrStatusRequestEntityTooLarge, err)
+
+	// Invalid limit
+	e.Binder = &DefaultBinder{BodyLimit: "2X"}
+	err = bind(strings.NewReader(`{"id":1}`), MIMEApplicationJSON)
+	if assert.IsType(t, new(HTTPError), err) {
+		assert.Equal(t, http.StatusInternalServerError, err.(*HTTPError).Code)
+	}
+}
+
+func TestBindDefault(t *testing.T) {
+	type paging struct {
+		Page    int      `query:"page" default:"1"`
+		PerPage int      `query:"per_page" default:"20"`
+		Sort    []string `query:"sort" default:"name,id"`
+		Order   *string  `query:"order" default:"asc"`
+		Query   string   `query:"q"`
+	}
+	e := New()
+	bind := func(target interface{}, url string, body string) error {
+		var r io.Reader
+		if body != "" {
+			r = strings.NewReader(body)
+		}
+		req := httptest.NewRequest(http.MethodPost, url, r)
+		req.Header.Set(HeaderContentType, MIMEApplicationJSON)
+		c := e.NewContext(req, httptest.NewRecorder())
+		return c.Bind(target)
+	}
+
+	p := new(paging)
+	if assert.NoError(t, bind(p, "/", "")) {
+		assert.Equal(t, 1, p.Page)
+		assert.Equal(t, 20, p.PerPage)
+		assert.Equal(t, []string{"name", "id"}, p.Sort)
+		if assert.NotNil(t, p.Order) {
+			assert.Equal(t, "asc", *p.Order)
+		}
+		assert.Equal(t, "", p.Query)
+	}
+
+	p = new(paging)
+	if assert.NoError(t, bind(p, "/?page=3&sort=id", "")) {
+		assert.Equal(t, 3, p.Page)
+		assert.Equal(t, 20, p.PerPage)
+		assert.Equal(t, []string{"id"}, p.Sort)
+	}
+
+	// Body values take precedence, missing ones keep their defaults
+	p = new(paging)
+	if assert.NoError(t, bind(p, "/", `{"PerPage":50}`)) {
+		assert.Equal(t, 1, p.Page)
+		assert.Equal(t, 50, p.PerPage)
+	}
+
+	// Granular helpers
+	p = new(paging)
+	req := httptest.NewRequest(http.MethodGet, "/?page=2", nil)
+	c := e.NewContext(req, httptest.NewRecorder())
+	if assert.NoError(t, new(DefaultBinder).BindQueryParams(p, c)) {
+		assert.Equal(t, 2, p.Page)
+		assert.Equal(t, 20, p.PerPage)
+	}
+	p = new(paging)
+	if assert.NoError(t, new(DefaultBinder).BindHeaders(p, c)) {
+		assert.Equal(t, 1, p.Page)
+		assert.Equal(t, []string{"name", "id"}, p.Sort)
+	}
+
+	// Invalid default
+	err := bind(&struct {
+		Page int `query:"page" default:"one"`
+	}{}, "/", "")
+	if assert.IsType(t, new(HTTPError), err) {
+		assert.Equal(t, http.StatusInternalServerError, err.(*HTTPError).Code)
+		assert.Contains(t, err.(*HTTPError).Message, "invalid default value for field Page")
+	}
+}
+
+func TestBindBindingError(t *testing.T) {
+	e := New()
+	req := httptest.NewRequest(http.MethodGet, "/?id=1&id=x&page=two", nil)
+	c := e.NewContext(req, httptest.NewRecorder())
+
+	err := c.Bind(&struct {
+		Page int `query:"page"`
+	}{})
+	var be *BindingError
+	if assert.True(t, errors.As(err, &be)) {
+		assert.Equal(t, "Page", be.Field)
+		assert.Equal(t, []string{"two"}, be.Values)
+		assert.Equal(t, "int", be.Expected)
+		assert.IsType(t, new(strconv.NumError), be.Err)
+		assert.Equal(t, `invalid value "two" for field Page, expected int: strconv.ParseInt: parsing "two": invalid syntax`, be.Error())
+		assert.Equal(t, be.Error(), err.(*HTTPError).Message)
+	}
+
+	err = c.Bind(&struct {
+		IDs []int `query:"id"`
+	}{})
+	if assert.True(t, errors.As(err, &be)) {
+		assert.Equal(t, "IDs", be.Field)
+		assert.Equal(t, []string{"1", "x"}, be.Values)
+		assert.Equal(t, "[]int", be.Expected)
+	}
+}
+
+func TestBindCollectErrors(t *testing.T) {
+	type item struct {
+		Qty int `query:"qty"`
+	}
+	type target struct {
+		ID    int       `param:"id"`
+		Page  int       `query:"page"`
+		Limit uint      `query:"limit"`
+		Debug bool      `header:"X-Debug"`
+		Items []item    `query:"items"`
+		Name  string    `query:"name"`
+		Since time.Time `query:"since" time_format:"2006-01-02"`
+	}
+	e := New()
+	req := httptest.NewRequest(http.MethodGet, "/?page=x&limit=-1&name=jon&since=yesterday&items[0].qty=z", nil)
+	req.Header.Set("X-Debug", "maybe")
+	c := e.NewContext(req, httptest.NewRecorder())
+	c.SetParamNames("id")
+	c.SetParamValues("abc")
+
+	// First error only
+	err := c.Bind(new(target))
+	if assert.IsType(t, new(HTTPError), err) {
+		assert.Equal(t, http.StatusBadRequest, err.(*HTTPError).Code)
+		assert.IsType(t, new(BindingError), err.(*HTTPError).Internal)
+	}
+
+	e.Binder = &DefaultBinder{CollectErrors: true}
+	v := new(target)
+	err = c.Bind(v)
+	if assert.IsType(t, new(HTTPError), err) {
+		he := err.(*HTTPError)
+		assert.Equal(t, http.StatusBadRequest, he.Code)
+		errs, ok := he.Internal.(FieldErrors)
+		if assert.True(t, ok) && assert.Len(t, errs, 6) {
+			fields := make([]string, len(errs))
+			for i, fe := range errs {
+				fields[i] = fe.Source + " " + fe.Field
+			}
+			assert.Equal(t, []string{"param id", "query page", "query limit", "query items[0].qty", "query since", "header X-Debug"}, fields)
+			for _, fe := range errs {
+				assert.IsType(t, new(BindingError), fe.Err)
+			}
+		}
+		assert.Equal(t, errs, he.Message.(Map)["errors"])
+	}
+	// Valid values are still bound
+	assert.Equal(t, "jon", v.Name)
+
+	// Valid request
+	req = httptest.NewRequest(http.MethodGet, "/?page=2", nil)
+	c = e.NewContext(req, httptest.NewRecorder())
+	if assert.NoError(t, c.Bind(v)) {
+		assert.Equal(t, 2, v.Page)
+	}
+}
+
 func TestBindSetWithProperType(t *testing.T) {
 	assert := assert.New(t)
 	ts := new(bindTestStruct)
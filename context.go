@@ -2,18 +2,27 @@ package echo
 
 import (
 	"bytes"
+	stdContext "context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
 	"encoding/json"
 	"encoding/xml"
 	"fmt"
+	"html"
 	"io"
+	"mime"
 	"mime/multipart"
 	"net"
 	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 )
 
 type (
@@ -38,6 +47,37 @@ type (
 		// IsWebSocket returns true if HTTP connection is WebSocket otherwise false.
 		IsWebSocket() bool
 
+		// WebSocketSubprotocol negotiates the WebSocket subprotocol, picking the
+		// first of the protocols requested by the client with the
+		// `Sec-WebSocket-Protocol` header which is supported by the server. The
+		// chosen protocol is set as response header and returned, or an empty
+		// string is returned if none match.
+		WebSocketSubprotocol(supported ...string) string
+
+		// Negotiate sends i with status code in the format preferred by the
+		// client according to the `Accept` header and its q-values: JSON, XML,
+		// HTML or plain text. Offers restricts the formats, by default all of
+		// them in that order, which is the order wildcards like `*/*` match
+		// them in. If none is acceptable, `Echo#DefaultFormat` is used or
+		// "406 - Not Acceptable" error is returned.
+		Negotiate(code int, i interface{}, offers ...string) error
+
+		// AcceptsLanguages returns the languages of the `Accept-Language` header
+		// ordered by preference, leaving out those with q=0.
+		AcceptsLanguages() []string
+
+		// AcceptsEncodings returns the encodings of the `Accept-Encoding` header
+		// ordered by preference, leaving out those with q=0.
+		AcceptsEncodings() []string
+
+		// WebSocket upgrades the connection to the WebSocket protocol and calls
+		// handler with it, closing it afterwards. A subprotocol chosen with
+		// `WebSocketSubprotocol()` is confirmed to the client. Invalid
+		// handshakes are rejected with "400 - Bad Request" error, and ones
+		// from a disallowed origin, see `Echo#WebSocketCheckOrigin`, with
+		// "403 - Forbidden" error.
+		WebSocket(handler func(WebSocketConn) error) error
+
 		// Scheme returns the HTTP protocol scheme, `http` or `https`.
 		Scheme() string
 
@@ -45,12 +85,24 @@ type (
 		// or `X-Real-IP` request header.
 		RealIP() string
 
+		// SetRealIP overrides the client's network address returned by `RealIP()`
+		// for the remainder of the request.
+		SetRealIP(ip string)
+
 		// Path returns the registered path for the handler.
 		Path() string
 
 		// SetPath sets the registered path for the handler.
 		SetPath(p string)
 
+		// RouteName returns the name of the route matched for the request, falling
+		// back to the registered path if the route has no name.
+		RouteName() string
+
+		// RouteMeta returns the metadata of the route matched for the request, or
+		// nil if no route matched. See `Route#Meta`.
+		RouteMeta() map[string]interface{}
+
 		// Param returns path parameter by name.
 		Param(name string) string
 
@@ -69,9 +121,25 @@ type (
 		// QueryParam returns the query param for the provided name.
 		QueryParam(name string) string
 
+		// QueryParamValues returns all the query param values for the provided name.
+		QueryParamValues(name string) []string
+
+		// QueryParamExists returns true if the query param for the provided name
+		// is present, even if its value is empty.
+		QueryParamExists(name string) bool
+
 		// QueryParams returns the query parameters as `url.Values`.
 		QueryParams() url.Values
 
+		// QueryParamsWithPrefix returns the query params whose name starts with
+		// prefix, with the prefix stripped from their names.
+		QueryParamsWithPrefix(prefix string) map[string][]string
+
+		// QueryParamKeys returns the query param names in the order they first
+		// appear in the URL query string, as `Context#QueryParams()` doesn't
+		// preserve it. Use it to process query params deterministically.
+		QueryParamKeys() []string
+
 		// QueryString returns the URL query string.
 		QueryString() string
 
@@ -87,6 +155,12 @@ type (
 		// MultipartForm returns the multipart form.
 		MultipartForm() (*multipart.Form, error)
 
+		// MultipartReader returns a `*multipart.Reader` to process a multipart/form-data
+		// request body as a stream, one part at a time, without buffering it in
+		// memory or on disk. It consumes the request body, so it can't be used
+		// along with `Context#Bind()`, `Context#FormValue()` or `Context#MultipartForm()`.
+		MultipartReader() (*multipart.Reader, error)
+
 		// Cookie returns the named cookie provided in the request.
 		Cookie(name string) (*http.Cookie, error)
 
@@ -96,22 +170,81 @@ type (
 		// Cookies returns the HTTP cookies sent with the request.
 		Cookies() []*http.Cookie
 
+		// SetCookieSigned adds a `Set-Cookie` header in HTTP response with the
+		// cookie value signed using the first key of `Echo#CookieSigningKeys`.
+		// The cookie expiry, taken from `Expires` or `MaxAge`, is embedded in the
+		// signed value so it is enforced by `GetCookieSigned()`.
+		SetCookieSigned(cookie *http.Cookie) error
+
+		// GetCookieSigned returns the named cookie provided in the request with
+		// its signature verified and value decoded. Any of `Echo#CookieSigningKeys`
+		// is accepted to allow key rotation. It returns `ErrCookieExpired` once the
+		// embedded expiry has passed.
+		GetCookieSigned(name string) (*http.Cookie, error)
+
 		// Get retrieves data from the context.
 		Get(key string) interface{}
 
 		// Set saves data in the context.
 		Set(key string, val interface{})
 
+		// Keys returns the sorted keys of the data saved in the context.
+		Keys() []string
+
+		// ResetStore clears all the data saved in the context. It is intended
+		// for reusing a context, e.g. in tests, as middleware and handlers
+		// earlier in the chain may rely on the data. Contexts pooled by Echo are
+		// cleared by `Reset()` before being reused.
+		ResetStore()
+
+		// SetContextValue saves data in the context like `Set()`, and also in
+		// the `context.Context` of the request, where code which has no access
+		// to the Echo context can retrieve it with `FromContext()`. Each call
+		// derives a new `context.Context` and a shallow copy of the request, so
+		// `Set()` should be preferred for data used only by handlers.
+		SetContextValue(key string, val interface{})
+
 		// Bind binds the request body into provided type `i`. The default binder
-		// does it based on Content-Type header.
+		// does it based on Content-Type header. A binder set for the route with
+		// `WithBinder()` takes precedence over `Echo#Binder`. If
+		// `Echo#ValidateOnBind` is set, values with a `Validate() error` method
+		// are validated after binding.
 		Bind(i interface{}) error
 
+		// BindPathParams binds only path params into provided type `i`, so
+		// handlers can compose which sources populate it, e.g. path params
+		// after the body. The options of the route or Echo binder apply if it's
+		// a `*DefaultBinder`.
+		BindPathParams(i interface{}) error
+
+		// BindQueryParams binds only query params into provided type `i`.
+		// See `BindPathParams()`.
+		BindQueryParams(i interface{}) error
+
+		// BindHeaders binds only request headers into provided type `i`.
+		// See `BindPathParams()`.
+		BindHeaders(i interface{}) error
+
+		// BindBody binds only the request body into provided type `i`.
+		// See `BindPathParams()`.
+		BindBody(i interface{}) error
+
 		// Validate validates provided `i`. It is usually called after `Context#Bind()`.
 		// Validator must be registered using `Echo#Validator`.
 		Validate(i interface{}) error
 
+		// BindAndValidate binds the request into `i` like `Bind()` and then
+		// validates it like `Validate()`. Validation errors which aren't an
+		// `*HTTPError` are returned as "422 - Unprocessable Entity" error.
+		BindAndValidate(i interface{}) error
+
+		// SetContentType sets the response `Content-Type` header. A UTF-8 charset
+		// is appended for text-based media types which don't specify one.
+		SetContentType(mime string)
+
 		// Render renders a template with data and sends a text/html response with status
-		// code. Renderer must be registered using `Echo.Renderer`.
+		// code. Renderer must be registered using `Echo.Renderer`, or `Group#Renderer`
+		// for the routes of a group.
 		Render(code int, name string, data interface{}) error
 
 		// HTML sends an HTTP response with status code.
@@ -155,6 +288,14 @@ type (
 		// Stream sends a streaming response with status code and content type.
 		Stream(code int, contentType string, r io.Reader) error
 
+		// FlushEvery makes the response flush written data at most d after it
+		// was written, so clients of long-polling and streaming endpoints get
+		// it without waiting for buffers to fill up. A d of zero flushes every
+		// write. The returned function stops it, flushing pending data, and must
+		// be called before the handler returns, usually deferred. It does
+		// nothing if the response writer doesn't support flushing.
+		FlushEvery(d time.Duration) (stop func())
+
 		// File sends a response with the content of the file.
 		File(file string) error
 
@@ -162,6 +303,18 @@ type (
 		// file.
 		Attachment(file string, name string) error
 
+		// AttachmentStream sends the content of r as attachment of size bytes,
+		// prompting client to save it as name. If progress is not nil, it is
+		// called with the number of bytes sent so far as the content is copied.
+		// It returns `io.ErrUnexpectedEOF` if r has less than size bytes.
+		AttachmentStream(r io.Reader, name string, size int64, progress func(sent, total int64)) error
+
+		// SSE starts a Server-Sent Events response, returning the stream to
+		// send events with. Heartbeats are sent every `DefaultSSEHeartbeat`
+		// until the stream is closed, which the handler must do before
+		// returning. It fails if the response writer doesn't support flushing.
+		SSE() (*SSE, error)
+
 		// Inline sends a response as inline, opening the file in the browser.
 		Inline(file string, name string) error
 
@@ -171,6 +324,18 @@ type (
 		// Redirect redirects the request to a provided URL with status code.
 		Redirect(code int, url string) error
 
+		// Push initiates an HTTP/2 server push of target, e.g. a stylesheet used
+		// by the page being sent, with `http.Pusher`. It returns
+		// `http.ErrNotSupported` if the connection doesn't support push.
+		Push(target string, opts *http.PushOptions) error
+
+		// Deprecation marks the requested endpoint as deprecated by setting the
+		// `Deprecation` response header (RFC 9745) to the time the endpoint was
+		// or will be deprecated, the `Sunset` header (RFC 8594) to the time it
+		// becomes unresponsive and a `Link` header to the migration
+		// documentation. A zero time or an empty link is omitted.
+		Deprecation(date, sunset time.Time, link string)
+
 		// Error invokes the registered HTTP error handler. Generally used by middleware.
 		Error(err error)
 
@@ -190,6 +355,23 @@ type (
 		// with `Echo#AcquireContext()` and `Echo#ReleaseContext()`.
 		// See `Echo#ServeHTTP()`
 		Reset(r *http.Request, w http.ResponseWriter)
+
+		// matched returns the state of the matched route set by the framework,
+		// kept apart from the values of `Set()`. Contexts embedding `Context`
+		// share it.
+		matched() *routeState
+	}
+
+	// routeState is the state of the matched route, e.g. set by route-level
+	// middleware.
+	routeState struct {
+		// binder is set by `WithBinder()`.
+		binder Binder
+		// renderer is the renderer of the group of the route.
+		renderer Renderer
+		// allow is the value of the Allow header for requests with a method
+		// the route has no handler for.
+		allow string
 	}
 
 	context struct {
@@ -199,8 +381,10 @@ type (
 		pnames   []string
 		pvalues  []string
 		query    url.Values
+		realIP   string
 		handler  HandlerFunc
 		store    Map
+		state    routeState
 		echo     *Echo
 		lock     sync.RWMutex
 	}
@@ -219,6 +403,29 @@ func (c *context) writeContentType(value string) {
 	}
 }
 
+func (c *context) SetContentType(mime string) {
+	if isTextMIME(mime) && !strings.Contains(strings.ToLower(mime), "charset=") {
+		mime += "; " + charsetUTF8
+	}
+	c.response.Header().Set(HeaderContentType, mime)
+}
+
+// isTextMIME reports whether the media type is text-based and hence should
+// carry a charset.
+func isTextMIME(mime string) bool {
+	if i := strings.Index(mime, ";"); i != -1 {
+		mime = mime[:i]
+	}
+	mime = strings.ToLower(strings.TrimSpace(mime))
+	switch mime {
+	case MIMEApplicationJSON, MIMEApplicationJavaScript, MIMEApplicationXML, MIMEApplicationForm:
+		return true
+	}
+	return strings.HasPrefix(mime, "text/") ||
+		strings.HasSuffix(mime, "+json") ||
+		strings.HasSuffix(mime, "+xml")
+}
+
 func (c *context) Request() *http.Request {
 	return c.request
 }
@@ -244,6 +451,151 @@ func (c *context) IsWebSocket() bool {
 	return strings.ToLower(upgrade) == "websocket"
 }
 
+func (c *context) WebSocketSubprotocol(supported ...string) string {
+	for _, h := range c.request.Header[http.CanonicalHeaderKey(HeaderSecWebSocketProtocol)] {
+		for _, p := range strings.Split(h, ",") {
+			p = strings.TrimSpace(p)
+			for _, s := range supported {
+				if p == s {
+					c.response.Header().Set(HeaderSecWebSocketProtocol, p)
+					return p
+				}
+			}
+		}
+	}
+	return ""
+}
+
+func (c *context) Negotiate(code int, i interface{}, offers ...string) error {
+	format, err := c.negotiateFormat(offers)
+	if err != nil {
+		return err
+	}
+	switch format {
+	case MIMEApplicationJSON:
+		return c.JSON(code, i)
+	case MIMEApplicationXML, MIMETextXML:
+		return c.XML(code, i)
+	case MIMETextHTML:
+		if s, ok := i.(string); ok {
+			return c.HTML(code, s)
+		}
+		return c.HTML(code, html.EscapeString(fmt.Sprint(i)))
+	case MIMETextPlain:
+		return c.String(code, fmt.Sprint(i))
+	}
+	return NewHTTPError(http.StatusInternalServerError).SetInternal(fmt.Errorf("unsupported negotiation format %s", format))
+}
+
+var negotiateOffers = []string{MIMEApplicationJSON, MIMEApplicationXML, MIMETextHTML, MIMETextPlain}
+
+// negotiateFormat returns the first of offers, by default JSON, XML, HTML and
+// plain text, which is acceptable according to the `Accept` header. If none
+// is, `Echo#DefaultFormat` is returned or "406 - Not Acceptable" error if it
+// isn't set.
+func (c *context) negotiateFormat(offers []string) (string, error) {
+	if len(offers) == 0 {
+		offers = negotiateOffers
+	}
+	if format := negotiate(c.request.Header.Get(HeaderAccept), offers); format != "" {
+		return format, nil
+	}
+	if c.echo.DefaultFormat != "" {
+		return c.echo.DefaultFormat, nil
+	}
+	return "", ErrNotAcceptable
+}
+
+// negotiate returns the first of offers which is acceptable according to the
+// `Accept` header, or an empty string if none is.
+func negotiate(accept string, offers []string) string {
+	if strings.TrimSpace(accept) == "" {
+		return offers[0]
+	}
+	accepted, refused := parseQualities(accept)
+	for _, a := range accepted {
+		a = strings.ToLower(a)
+		for _, o := range offers {
+			if containsFold(refused, o) {
+				continue
+			}
+			if a == "*/*" || a == o || strings.HasSuffix(a, "/*") && strings.HasPrefix(o, a[:len(a)-1]) {
+				return o
+			}
+		}
+	}
+	return ""
+}
+
+func containsFold(values []string, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *context) AcceptsLanguages() []string {
+	languages, _ := parseQualities(strings.Join(c.request.Header[HeaderAcceptLanguage], ","))
+	return languages
+}
+
+func (c *context) AcceptsEncodings() []string {
+	encodings, _ := parseQualities(strings.Join(c.request.Header[HeaderAcceptEncoding], ","))
+	return encodings
+}
+
+// parseQualities parses a header with a comma separated list of values with
+// optional q-values, e.g. `en;q=0.8, de`, returning the accepted values ordered
+// by quality and the refused ones, with q=0. Values with the same quality keep
+// their order.
+func parseQualities(header string) (accepted, refused []string) {
+	type item struct {
+		value string
+		q     float64
+	}
+	var items []item
+	for _, part := range strings.Split(header, ",") {
+		params := strings.Split(part, ";")
+		value := strings.TrimSpace(params[0])
+		if value == "" {
+			continue
+		}
+		q := 1.0
+		for _, p := range params[1:] {
+			p = strings.TrimSpace(p)
+			if strings.HasPrefix(p, "q=") {
+				if f, err := strconv.ParseFloat(p[2:], 64); err == nil {
+					q = f
+				}
+			}
+		}
+		if q > 0 {
+			items = append(items, item{value, q})
+		} else {
+			refused = append(refused, value)
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].q > items[j].q
+	})
+	for _, it := range items {
+		accepted = append(accepted, it.value)
+	}
+	return
+}
+
+func (c *context) WebSocket(handler func(WebSocketConn) error) error {
+	ws, err := upgradeWebSocket(c)
+	if err != nil {
+		return err
+	}
+	err = handler(ws)
+	ws.Close(WebSocketCloseNormal, "")
+	return err
+}
+
 func (c *context) Scheme() string {
 	// Can't use `r.Request.URL.Scheme`
 	// See: https://groups.google.com/forum/#!topic/golang-nuts/pMUkBlQBDF0
@@ -266,6 +618,9 @@ func (c *context) Scheme() string {
 }
 
 func (c *context) RealIP() string {
+	if c.realIP != "" {
+		return c.realIP
+	}
 	if ip := c.request.Header.Get(HeaderXForwardedFor); ip != "" {
 		return strings.Split(ip, ", ")[0]
 	}
@@ -276,6 +631,10 @@ func (c *context) RealIP() string {
 	return ra
 }
 
+func (c *context) SetRealIP(ip string) {
+	c.realIP = ip
+}
+
 func (c *context) Path() string {
 	return c.path
 }
@@ -284,6 +643,25 @@ func (c *context) SetPath(p string) {
 	c.path = p
 }
 
+// route returns the registered route matched for the request, if any.
+func (c *context) route() *Route {
+	return c.echo.router.routes[c.request.Method+c.path]
+}
+
+func (c *context) RouteName() string {
+	if r := c.route(); r != nil && r.Name != "" {
+		return r.Name
+	}
+	return c.path
+}
+
+func (c *context) RouteMeta() map[string]interface{} {
+	if r := c.route(); r != nil {
+		return r.Meta
+	}
+	return nil
+}
+
 func (c *context) Param(name string) string {
 	for i, n := range c.pnames {
 		if i < len(c.pvalues) {
@@ -318,6 +696,15 @@ func (c *context) QueryParam(name string) string {
 	return c.query.Get(name)
 }
 
+func (c *context) QueryParamValues(name string) []string {
+	return c.QueryParams()[name]
+}
+
+func (c *context) QueryParamExists(name string) bool {
+	_, ok := c.QueryParams()[name]
+	return ok
+}
+
 func (c *context) QueryParams() url.Values {
 	if c.query == nil {
 		c.query = c.request.URL.Query()
@@ -325,6 +712,36 @@ func (c *context) QueryParams() url.Values {
 	return c.query
 }
 
+func (c *context) QueryParamsWithPrefix(prefix string) map[string][]string {
+	params := map[string][]string{}
+	for k, v := range c.QueryParams() {
+		if strings.HasPrefix(k, prefix) {
+			params[k[len(prefix):]] = v
+		}
+	}
+	return params
+}
+
+func (c *context) QueryParamKeys() []string {
+	keys := []string{}
+	seen := map[string]bool{}
+	for _, pair := range strings.Split(c.request.URL.RawQuery, "&") {
+		if pair == "" {
+			continue
+		}
+		if i := strings.Index(pair, "="); i != -1 {
+			pair = pair[:i]
+		}
+		key, err := url.QueryUnescape(pair)
+		if err != nil || seen[key] {
+			continue
+		}
+		seen[key] = true
+		keys = append(keys, key)
+	}
+	return keys
+}
+
 func (c *context) QueryString() string {
 	return c.request.URL.RawQuery
 }
@@ -356,6 +773,10 @@ func (c *context) MultipartForm() (*multipart.Form, error) {
 	return c.request.MultipartForm, err
 }
 
+func (c *context) MultipartReader() (*multipart.Reader, error) {
+	return c.request.MultipartReader()
+}
+
 func (c *context) Cookie(name string) (*http.Cookie, error) {
 	return c.request.Cookie(name)
 }
@@ -368,6 +789,81 @@ func (c *context) Cookies() []*http.Cookie {
 	return c.request.Cookies()
 }
 
+func (c *context) SetCookieSigned(cookie *http.Cookie) error {
+	keys := c.echo.CookieSigningKeys
+	if len(keys) == 0 {
+		return ErrCookieSigningKeyNotSet
+	}
+	var expires int64
+	if !cookie.Expires.IsZero() {
+		expires = cookie.Expires.Unix()
+	} else if cookie.MaxAge > 0 {
+		expires = time.Now().Add(time.Duration(cookie.MaxAge) * time.Second).Unix()
+	}
+	payload := strconv.FormatInt(expires, 10) + "|" + cookie.Value
+	signed := *cookie
+	signed.Value = base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." +
+		base64.RawURLEncoding.EncodeToString(signCookie(keys[0], cookie.Name, payload))
+	c.SetCookie(&signed)
+	return nil
+}
+
+func (c *context) GetCookieSigned(name string) (*http.Cookie, error) {
+	keys := c.echo.CookieSigningKeys
+	if len(keys) == 0 {
+		return nil, ErrCookieSigningKeyNotSet
+	}
+	cookie, err := c.Cookie(name)
+	if err != nil {
+		return nil, err
+	}
+	parts := strings.SplitN(cookie.Value, ".", 2)
+	if len(parts) != 2 {
+		return nil, ErrCookieInvalidSignature
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, ErrCookieInvalidSignature
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrCookieInvalidSignature
+	}
+	valid := false
+	for _, key := range keys {
+		if hmac.Equal(sig, signCookie(key, name, string(payload))) {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return nil, ErrCookieInvalidSignature
+	}
+	fields := strings.SplitN(string(payload), "|", 2)
+	if len(fields) != 2 {
+		return nil, ErrCookieInvalidSignature
+	}
+	expires, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return nil, ErrCookieInvalidSignature
+	}
+	if expires != 0 && time.Now().Unix() >= expires {
+		return nil, ErrCookieExpired
+	}
+	cookie.Value = fields[1]
+	if expires != 0 {
+		cookie.Expires = time.Unix(expires, 0)
+	}
+	return cookie, nil
+}
+
+// signCookie returns the HMAC-SHA256 of the cookie name and payload.
+func signCookie(key []byte, name, payload string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(name + "|" + payload))
+	return mac.Sum(nil)
+}
+
 func (c *context) Get(key string) interface{} {
 	c.lock.RLock()
 	defer c.lock.RUnlock()
@@ -384,8 +880,104 @@ func (c *context) Set(key string, val interface{}) {
 	c.store[key] = val
 }
 
-func (c *context) Bind(i interface{}) error {
-	return c.echo.Binder.Bind(i, c)
+func (c *context) Keys() []string {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	keys := make([]string, 0, len(c.store))
+	for k := range c.store {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (c *context) ResetStore() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.store = nil
+}
+
+func (c *context) SetContextValue(key string, val interface{}) {
+	c.Set(key, val)
+	c.request = c.request.WithContext(stdContext.WithValue(c.request.Context(), contextKey(key), val))
+}
+
+// contextKey is the type of the keys of values set with
+// `Context#SetContextValue()` in a `context.Context`.
+type contextKey string
+
+// FromContext retrieves data saved with `Context#SetContextValue()` from ctx or
+// any context derived from it.
+func FromContext(ctx stdContext.Context, key string) interface{} {
+	return ctx.Value(contextKey(key))
+}
+
+func (c *context) Bind(i interface{}) (err error) {
+	if b := c.state.binder; b != nil {
+		err = b.Bind(i, c)
+	} else {
+		err = c.echo.Binder.Bind(i, c)
+	}
+	if err != nil || !c.echo.ValidateOnBind {
+		return
+	}
+	if v, ok := i.(selfValidator); ok {
+		return validationError(v.Validate())
+	}
+	return
+}
+
+// selfValidator is implemented by bound values validating themselves, see
+// `Echo#ValidateOnBind`.
+type selfValidator interface {
+	Validate() error
+}
+
+// validationError converts a validation error into a "422 - Unprocessable
+// Entity" error, with the field errors of `FieldErrors` in the message.
+func validationError(err error) error {
+	switch e := err.(type) {
+	case nil:
+		return nil
+	case *HTTPError:
+		return e
+	case FieldErrors:
+		return NewHTTPError(http.StatusUnprocessableEntity, Map{"message": "Validation errors", "errors": e}).SetInternal(err)
+	case *FieldError:
+		return NewHTTPError(http.StatusUnprocessableEntity, Map{"message": "Validation errors", "errors": FieldErrors{e}}).SetInternal(err)
+	}
+	return NewHTTPError(http.StatusUnprocessableEntity, err.Error()).SetInternal(err)
+}
+
+func (c *context) BindPathParams(i interface{}) error {
+	return c.defaultBinder().BindPathParams(i, c)
+}
+
+func (c *context) BindQueryParams(i interface{}) error {
+	return c.defaultBinder().BindQueryParams(i, c)
+}
+
+func (c *context) BindHeaders(i interface{}) error {
+	return c.defaultBinder().BindHeaders(i, c)
+}
+
+func (c *context) BindBody(i interface{}) error {
+	return c.defaultBinder().BindBody(i, c)
+}
+
+// defaultBinder returns the binder of the route or Echo if it's a
+// `*DefaultBinder`, or a new one otherwise.
+func (c *context) defaultBinder() *DefaultBinder {
+	b := c.state.binder
+	if b == nil {
+		b = c.echo.Binder
+	}
+	if db, ok := b.(*DefaultBinder); ok {
+		return db
+	}
+	return new(DefaultBinder)
 }
 
 func (c *context) Validate(i interface{}) error {
@@ -395,12 +987,26 @@ func (c *context) Validate(i interface{}) error {
 	return c.echo.Validator.Validate(i)
 }
 
+func (c *context) BindAndValidate(i interface{}) error {
+	if err := c.Bind(i); err != nil {
+		return err
+	}
+	if c.echo.Validator == nil {
+		return ErrValidatorNotRegistered
+	}
+	return validationError(c.echo.Validator.Validate(i))
+}
+
 func (c *context) Render(code int, name string, data interface{}) (err error) {
-	if c.echo.Renderer == nil {
+	r := c.state.renderer
+	if r == nil {
+		r = c.echo.Renderer
+	}
+	if r == nil {
 		return ErrRendererNotRegistered
 	}
 	buf := new(bytes.Buffer)
-	if err = c.echo.Renderer.Render(buf, name, data, c); err != nil {
+	if err = r.Render(buf, name, data, c); err != nil {
 		return
 	}
 	return c.HTMLBlob(code, buf.Bytes())
@@ -419,33 +1025,67 @@ func (c *context) String(code int, s string) (err error) {
 }
 
 func (c *context) jsonPBlob(code int, callback string, i interface{}) (err error) {
-	enc := json.NewEncoder(c.response)
-	_, pretty := c.QueryParams()["pretty"]
-	if c.echo.Debug || pretty {
-		enc.SetIndent("", "  ")
-	}
-	c.writeContentType(MIMEApplicationJavaScriptCharsetUTF8)
-	c.response.WriteHeader(code)
-	if _, err = c.response.Write([]byte(callback + "(")); err != nil {
-		return
+	indent := ""
+	if _, pretty := c.QueryParams()["pretty"]; c.echo.Debug || pretty {
+		indent = defaultIndent
 	}
-	if err = enc.Encode(i); err != nil {
+	encode := func(w io.Writer) (err error) {
+		if _, err = w.Write([]byte(callback + "(")); err != nil {
+			return
+		}
+		if err = c.encodeJSON(w, i, indent); err != nil {
+			return
+		}
+		_, err = w.Write([]byte(");"))
 		return
 	}
-	if _, err = c.response.Write([]byte(");")); err != nil {
-		return
+	if c.echo.MaxResponseSize > 0 {
+		return c.blobCapped(code, MIMEApplicationJavaScriptCharsetUTF8, encode)
 	}
-	return
+	c.writeContentType(MIMEApplicationJavaScriptCharsetUTF8)
+	c.response.WriteHeader(code)
+	return encode(c.response)
 }
 
 func (c *context) json(code int, i interface{}, indent string) error {
-	enc := json.NewEncoder(c.response)
-	if indent != "" {
-		enc.SetIndent("", indent)
+	if c.echo.MaxResponseSize > 0 {
+		return c.blobCapped(code, MIMEApplicationJSONCharsetUTF8, func(w io.Writer) error {
+			return c.encodeJSON(w, i, indent)
+		})
 	}
 	c.writeContentType(MIMEApplicationJSONCharsetUTF8)
 	c.response.Status = code
-	return enc.Encode(i)
+	return c.encodeJSON(c.response, i, indent)
+}
+
+// encodeJSON writes the JSON encoding of i to w, honouring the JSON options
+// of the Echo instance.
+func (c *context) encodeJSON(w io.Writer, i interface{}, indent string) error {
+	out := w
+	var buf *bytes.Buffer
+	if c.echo.DisableJSONTrailingNewline {
+		buf = new(bytes.Buffer)
+		out = buf
+	}
+	if s := c.echo.JSONSerializer; s != nil {
+		if err := s.Serialize(out, i, indent); err != nil {
+			return err
+		}
+	} else {
+		enc := json.NewEncoder(out)
+		enc.SetEscapeHTML(!c.echo.DisableJSONHTMLEscape)
+		if indent != "" {
+			enc.SetIndent("", indent)
+		}
+		if err := enc.Encode(i); err != nil {
+			return err
+		}
+	}
+	if buf != nil {
+		_, err := w.Write(bytes.TrimSuffix(buf.Bytes(), []byte("\n")))
+		return err
+	}
+	return nil
 }
 
 func (c *context) JSON(code int, i interface{}) (err error) {
@@ -482,16 +1122,22 @@ func (c *context) JSONPBlob(code int, callback string, b []byte) (err error) {
 }
 
 func (c *context) xml(code int, i interface{}, indent string) (err error) {
-	c.writeContentType(MIMEApplicationXMLCharsetUTF8)
-	c.response.WriteHeader(code)
-	enc := xml.NewEncoder(c.response)
-	if indent != "" {
-		enc.Indent("", indent)
+	encode := func(w io.Writer) (err error) {
+		enc := xml.NewEncoder(w)
+		if indent != "" {
+			enc.Indent("", indent)
+		}
+		if _, err = w.Write([]byte(xml.Header)); err != nil {
+			return
+		}
+		return enc.Encode(i)
 	}
-	if _, err = c.response.Write([]byte(xml.Header)); err != nil {
-		return
+	if c.echo.MaxResponseSize > 0 {
+		return c.blobCapped(code, MIMEApplicationXMLCharsetUTF8, encode)
 	}
-	return enc.Encode(i)
+	c.writeContentType(MIMEApplicationXMLCharsetUTF8)
+	c.response.WriteHeader(code)
+	return encode(c.response)
 }
 
 func (c *context) XML(code int, i interface{}) (err error) {
@@ -516,6 +1162,39 @@ func (c *context) XMLBlob(code int, b []byte) (err error) {
 	return
 }
 
+// blobCapped buffers the output of encode, aborting once it exceeds
+// `Echo#MaxResponseSize`, and sends it as a blob response. Nothing is written
+// to the response if the limit is exceeded.
+func (c *context) blobCapped(code int, contentType string, encode func(io.Writer) error) error {
+	buf := new(bytes.Buffer)
+	if err := encode(&cappedWriter{writer: buf, limit: c.echo.MaxResponseSize}); err != nil {
+		if err == ErrResponseTooLarge {
+			c.Logger().Errorf("response exceeds maximum size of %d bytes: %s %s",
+				c.echo.MaxResponseSize, c.request.Method, c.request.URL)
+			return NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+		}
+		return err
+	}
+	return c.Blob(code, contentType, buf.Bytes())
+}
+
+// cappedWriter writes to writer until limit bytes were written, after which
+// writes fail with `ErrResponseTooLarge`.
+type cappedWriter struct {
+	writer  io.Writer
+	limit   int64
+	written int64
+}
+
+func (w *cappedWriter) Write(b []byte) (int, error) {
+	if w.written+int64(len(b)) > w.limit {
+		return 0, ErrResponseTooLarge
+	}
+	n, err := w.writer.Write(b)
+	w.written += int64(n)
+	return n, err
+}
+
 func (c *context) Blob(code int, contentType string, b []byte) (err error) {
 	c.writeContentType(contentType)
 	c.response.WriteHeader(code)
@@ -530,6 +1209,21 @@ func (c *context) Stream(code int, contentType string, r io.Reader) (err error)
 	return
 }
 
+func (c *context) FlushEvery(d time.Duration) func() {
+	f, ok := c.response.Writer.(http.Flusher)
+	if !ok {
+		return func() {}
+	}
+	w := &intervalFlushWriter{ResponseWriter: c.response.Writer, flusher: f, interval: d}
+	c.response.Writer = w
+	return func() {
+		w.stop()
+		if c.response.Writer == w {
+			c.response.Writer = w.ResponseWriter
+		}
+	}
+}
+
 func (c *context) File(file string) (err error) {
 	f, err := os.Open(file)
 	if err != nil {
@@ -549,28 +1243,120 @@ func (c *context) File(file string) (err error) {
 			return
 		}
 	}
+	// Lets `http.ServeContent()` answer conditional requests with If-None-Match
+	if h := c.Response().Header(); h.Get(HeaderETag) == "" {
+		h.Set(HeaderETag, fmt.Sprintf(`W/"%x-%x"`, fi.ModTime().UnixNano(), fi.Size()))
+	}
 	http.ServeContent(c.Response(), c.Request(), fi.Name(), fi.ModTime(), f)
 	return
 }
 
+func (c *context) SSE() (*SSE, error) {
+	return newSSE(c)
+}
+
 func (c *context) Attachment(file, name string) error {
 	return c.contentDisposition(file, name, "attachment")
 }
 
+func (c *context) AttachmentStream(r io.Reader, name string, size int64, progress func(sent, total int64)) (err error) {
+	ctype := mime.TypeByExtension(filepath.Ext(name))
+	if ctype == "" {
+		ctype = MIMEOctetStream
+	}
+	header := c.response.Header()
+	header.Set(HeaderContentDisposition, contentDisposition("attachment", name))
+	header.Set(HeaderContentLength, strconv.FormatInt(size, 10))
+	c.writeContentType(ctype)
+	c.response.WriteHeader(http.StatusOK)
+
+	var w io.Writer = c.response
+	if progress != nil {
+		w = &progressWriter{writer: w, total: size, progress: progress}
+	}
+	n, err := io.Copy(w, io.LimitReader(r, size))
+	if err == nil && n < size {
+		err = io.ErrUnexpectedEOF
+	}
+	return
+}
+
+// progressWriter reports the number of bytes written after each write.
+type progressWriter struct {
+	writer   io.Writer
+	sent     int64
+	total    int64
+	progress func(sent, total int64)
+}
+
+func (w *progressWriter) Write(b []byte) (int, error) {
+	n, err := w.writer.Write(b)
+	w.sent += int64(n)
+	w.progress(w.sent, w.total)
+	return n, err
+}
+
 func (c *context) Inline(file, name string) error {
 	return c.contentDisposition(file, name, "inline")
 }
 
 func (c *context) contentDisposition(file, name, dispositionType string) error {
-	c.response.Header().Set(HeaderContentDisposition, fmt.Sprintf("%s; filename=%q", dispositionType, name))
+	c.response.Header().Set(HeaderContentDisposition, contentDisposition(dispositionType, name))
 	return c.File(file)
 }
 
+// contentDisposition formats a `Content-Disposition` header value. Names with
+// non-ASCII characters get an ASCII fallback `filename` for old clients and
+// the UTF-8 encoded `filename*` of RFC 5987.
+func contentDisposition(dispositionType, name string) string {
+	fallback := make([]byte, 0, len(name))
+	ascii := true
+	for _, r := range name {
+		switch {
+		case r < 0x20 || r >= 0x7f:
+			ascii = false
+			fallback = append(fallback, '_')
+		case r == '"' || r == '\\':
+			fallback = append(fallback, '\\', byte(r))
+		default:
+			fallback = append(fallback, byte(r))
+		}
+	}
+	v := dispositionType + `; filename="` + string(fallback) + `"`
+	if !ascii {
+		v += "; filename*=UTF-8''" + encodeRFC5987(name)
+	}
+	return v
+}
+
+// encodeRFC5987 percent-encodes s except for the attr-chars of RFC 5987.
+func encodeRFC5987(s string) string {
+	const hex = "0123456789ABCDEF"
+	b := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		if 'a' <= ch && ch <= 'z' || 'A' <= ch && ch <= 'Z' || '0' <= ch && ch <= '9' ||
+			strings.IndexByte("!#$&+-.^_`|~", ch) >= 0 {
+			b = append(b, ch)
+		} else {
+			b = append(b, '%', hex[ch>>4], hex[ch&0xf])
+		}
+	}
+	return string(b)
+}
+
 func (c *context) NoContent(code int) error {
 	c.response.WriteHeader(code)
 	return nil
 }
 
+func (c *context) Push(target string, opts *http.PushOptions) error {
+	if p, ok := c.response.Writer.(http.Pusher); ok {
+		return p.Push(target, opts)
+	}
+	return http.ErrNotSupported
+}
+
 func (c *context) Redirect(code int, url string) error {
 	if code < 300 || code > 308 {
 		return ErrInvalidRedirectCode
@@ -580,10 +1366,27 @@ func (c *context) Redirect(code int, url string) error {
 	return nil
 }
 
+func (c *context) Deprecation(date, sunset time.Time, link string) {
+	header := c.response.Header()
+	if !date.IsZero() {
+		header.Set(HeaderDeprecation, "@"+strconv.FormatInt(date.Unix(), 10))
+	}
+	if !sunset.IsZero() {
+		header.Set(HeaderSunset, sunset.UTC().Format(http.TimeFormat))
+	}
+	if link != "" {
+		header.Add(HeaderLink, fmt.Sprintf(`<%s>; rel="deprecation"`, link))
+	}
+}
+
 func (c *context) Error(err error) {
 	c.echo.HTTPErrorHandler(err, c)
 }
 
+func (c *context) matched() *routeState {
+	return &c.state
+}
+
 func (c *context) Echo() *Echo {
 	return c.echo
 }
@@ -604,8 +1407,10 @@ func (c *context) Reset(r *http.Request, w http.ResponseWriter) {
 	c.request = r
 	c.response.reset(w)
 	c.query = nil
+	c.realIP = ""
 	c.handler = NotFoundHandler
 	c.store = nil
+	c.state = routeState{}
 	c.path = ""
 	c.pnames = nil
 	// NOTE: Don't reset because it has to have length c.echo.maxParam at all times
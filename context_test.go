@@ -2,18 +2,22 @@ package echo
 
 import (
 	"bytes"
+	stdContext "context"
 	"crypto/tls"
 	"encoding/json"
 	"encoding/xml"
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"math"
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"text/template"
 	"time"
@@ -340,6 +344,31 @@ func TestContext(t *testing.T) {
 		assert.Equal(219885, rec.Body.Len())
 	}
 
+	// AttachmentStream
+	rec = httptest.NewRecorder()
+	c = e.NewContext(req, rec).(*context)
+	var sent []int64
+	payload := bytes.Repeat([]byte("echo"), 64<<10)
+	err = c.AttachmentStream(bytes.NewReader(payload), "data.bin", int64(len(payload)), func(n, total int64) {
+		assert.Equal(int64(len(payload)), total)
+		sent = append(sent, n)
+	})
+	if assert.NoError(err) {
+		assert.Equal(http.StatusOK, rec.Code)
+		assert.Equal("attachment; filename=\"data.bin\"", rec.Header().Get(HeaderContentDisposition))
+		assert.Equal(strconv.Itoa(len(payload)), rec.Header().Get(HeaderContentLength))
+		assert.Equal(MIMEOctetStream, rec.Header().Get(HeaderContentType))
+		assert.Equal(payload, rec.Body.Bytes())
+		assert.True(len(sent) > 1)
+		assert.Equal(int64(len(payload)), sent[len(sent)-1])
+	}
+
+	// AttachmentStream with a short reader
+	rec = httptest.NewRecorder()
+	c = e.NewContext(req, rec).(*context)
+	err = c.AttachmentStream(bytes.NewReader(payload), "data.bin", int64(len(payload)+1), nil)
+	assert.Equal(io.ErrUnexpectedEOF, err)
+
 	// Inline
 	rec = httptest.NewRecorder()
 	c = e.NewContext(req, rec).(*context)
@@ -403,6 +432,126 @@ func TestContext_JSON_DoesntCommitResponseCodePrematurely(t *testing.T) {
 	}
 }
 
+func TestContext_JSON_EscapeHTML(t *testing.T) {
+	e := New()
+	data := Map{"url": "https://example.com/?a=1&b=<2>"}
+
+	// Default
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+	if testify.NoError(t, c.JSON(http.StatusOK, data)) {
+		testify.Equal(t, `{"url":"https://example.com/?a=1\u0026b=\u003c2\u003e"}`+"\n", rec.Body.String())
+	}
+
+	// Disabled
+	e.DisableJSONHTMLEscape = true
+	rec = httptest.NewRecorder()
+	c = e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+	if testify.NoError(t, c.JSON(http.StatusOK, data)) {
+		testify.Equal(t, `{"url":"https://example.com/?a=1&b=<2>"}`+"\n", rec.Body.String())
+	}
+}
+
+func TestContext_JSON_TrailingNewline(t *testing.T) {
+	e := New()
+	e.DisableJSONTrailingNewline = true
+
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+	if testify.NoError(t, c.JSON(http.StatusCreated, testUser)) {
+		testify.Equal(t, http.StatusCreated, rec.Code)
+		testify.Equal(t, userJSON, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	c = e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+	if testify.NoError(t, c.JSONPretty(http.StatusOK, testUser, "  ")) {
+		testify.Equal(t, userJSONPretty, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	c = e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+	if testify.NoError(t, c.JSONP(http.StatusOK, "callback", testUser)) {
+		testify.Equal(t, "callback("+userJSON+");", rec.Body.String())
+	}
+}
+
+func TestContextSetContentType(t *testing.T) {
+	e := New()
+	for mime, expected := range map[string]string{
+		MIMEApplicationJSON:            MIMEApplicationJSONCharsetUTF8,
+		MIMETextHTML:                   MIMETextHTMLCharsetUTF8,
+		MIMETextPlain:                  MIMETextPlainCharsetUTF8,
+		"application/problem+json":     "application/problem+json; charset=UTF-8",
+		"text/csv; charset=ISO-8859-1": "text/csv; charset=ISO-8859-1",
+		MIMEOctetStream:                MIMEOctetStream,
+		"image/png":                    "image/png",
+	} {
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+		c.SetContentType(mime)
+		testify.Equal(t, expected, rec.Header().Get(HeaderContentType), mime)
+	}
+
+	// Response helpers
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+	testify.NoError(t, c.String(http.StatusOK, "test"))
+	testify.Equal(t, MIMETextPlainCharsetUTF8, rec.Header().Get(HeaderContentType))
+
+	rec = httptest.NewRecorder()
+	c = e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+	testify.NoError(t, c.HTML(http.StatusOK, "<p>test</p>"))
+	testify.Equal(t, MIMETextHTMLCharsetUTF8, rec.Header().Get(HeaderContentType))
+
+	rec = httptest.NewRecorder()
+	c = e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+	c.SetContentType("text/csv")
+	testify.NoError(t, c.Stream(http.StatusOK, MIMEOctetStream, strings.NewReader("a,b")))
+	testify.Equal(t, "text/csv; charset=UTF-8", rec.Header().Get(HeaderContentType))
+}
+
+func TestContextMaxResponseSize(t *testing.T) {
+	e := New()
+	e.MaxResponseSize = 64
+	assert := testify.New(t)
+
+	// Within limit
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+	if assert.NoError(c.JSON(http.StatusCreated, testUser)) {
+		assert.Equal(http.StatusCreated, rec.Code)
+		assert.Equal(MIMEApplicationJSONCharsetUTF8, rec.Header().Get(HeaderContentType))
+		assert.Equal(userJSON+"\n", rec.Body.String())
+	}
+
+	type list struct {
+		S []string
+	}
+	big := make([]string, 100)
+	for name, respond := range map[string]func(Context) error{
+		"JSON": func(c Context) error {
+			return c.JSON(http.StatusOK, big)
+		},
+		"JSONP": func(c Context) error {
+			return c.JSONP(http.StatusOK, "callback", big)
+		},
+		"XML": func(c Context) error {
+			return c.XML(http.StatusOK, list{big})
+		},
+	} {
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+		err := respond(c)
+		if assert.IsType(new(HTTPError), err, name) {
+			assert.Equal(http.StatusInternalServerError, err.(*HTTPError).Code, name)
+			assert.Equal(ErrResponseTooLarge, err.(*HTTPError).Internal, name)
+		}
+		assert.False(c.Response().Committed, name)
+		assert.Empty(rec.Body.String(), name)
+	}
+}
+
 func TestContextCookie(t *testing.T) {
 	e := New()
 	req := httptest.NewRequest(http.MethodGet, "/", nil)
@@ -450,6 +599,75 @@ func TestContextCookie(t *testing.T) {
 	assert.Contains(rec.Header().Get(HeaderSetCookie), "HttpOnly")
 }
 
+func TestContextCookieSigned(t *testing.T) {
+	e := New()
+	assert := testify.New(t)
+
+	// Signing key not set
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+	assert.Equal(ErrCookieSigningKeyNotSet, c.SetCookieSigned(&http.Cookie{Name: "session", Value: "jon"}))
+
+	e.CookieSigningKeys = [][]byte{[]byte("secret")}
+	signed := func(cookie *http.Cookie) string {
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+		assert.NoError(c.SetCookieSigned(cookie))
+		return rec.Header().Get(HeaderSetCookie)
+	}
+	get := func(header string) (*http.Cookie, error) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(HeaderCookie, header)
+		c := e.NewContext(req, httptest.NewRecorder())
+		return c.GetCookieSigned("session")
+	}
+	cookieHeader := func(setCookie string) string {
+		return strings.SplitN(setCookie, ";", 2)[0]
+	}
+
+	// Valid
+	h := cookieHeader(signed(&http.Cookie{Name: "session", Value: "jon", MaxAge: 60}))
+	cookie, err := get(h)
+	if assert.NoError(err) {
+		assert.Equal("jon", cookie.Value)
+	}
+
+	// Valid without expiry
+	cookie, err = get(cookieHeader(signed(&http.Cookie{Name: "session", Value: "jon"})))
+	if assert.NoError(err) {
+		assert.Equal("jon", cookie.Value)
+	}
+
+	// Tampered
+	_, err = get(strings.Replace(h, "session=", "session=x", 1))
+	assert.Equal(ErrCookieInvalidSignature, err)
+	_, err = get(h + "x")
+	assert.Equal(ErrCookieInvalidSignature, err)
+	_, err = get("session=jon")
+	assert.Equal(ErrCookieInvalidSignature, err)
+
+	// Signed for a different cookie name
+	_, err = get(strings.Replace(cookieHeader(signed(&http.Cookie{Name: "other", Value: "jon"})), "other=", "session=", 1))
+	assert.Equal(ErrCookieInvalidSignature, err)
+
+	// Expired
+	_, err = get(cookieHeader(signed(&http.Cookie{Name: "session", Value: "jon", Expires: time.Now().Add(-time.Minute)})))
+	assert.Equal(ErrCookieExpired, err)
+
+	// Key rotation
+	e.CookieSigningKeys = [][]byte{[]byte("new-secret"), []byte("secret")}
+	cookie, err = get(h)
+	if assert.NoError(err) {
+		assert.Equal("jon", cookie.Value)
+	}
+	e.CookieSigningKeys = [][]byte{[]byte("new-secret")}
+	_, err = get(h)
+	assert.Equal(ErrCookieInvalidSignature, err)
+
+	// Missing
+	_, err = get("theme=light")
+	assert.Equal(http.ErrNoCookie, err)
+}
+
 func TestContextPath(t *testing.T) {
 	e := New()
 	r := e.Router()
@@ -537,6 +755,64 @@ func TestContextQueryParam(t *testing.T) {
 	}, c.QueryParams())
 }
 
+func TestContextQueryParamValues(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/?tag=a&tag=b&name=Jon&empty=", nil)
+	e := New()
+	c := e.NewContext(req, nil)
+
+	// Repeated
+	testify.Equal(t, []string{"a", "b"}, c.QueryParamValues("tag"))
+	testify.True(t, c.QueryParamExists("tag"))
+
+	// Single
+	testify.Equal(t, []string{"Jon"}, c.QueryParamValues("name"))
+	testify.True(t, c.QueryParamExists("name"))
+
+	// Empty
+	testify.Equal(t, []string{""}, c.QueryParamValues("empty"))
+	testify.True(t, c.QueryParamExists("empty"))
+
+	// Absent
+	testify.Nil(t, c.QueryParamValues("missing"))
+	testify.False(t, c.QueryParamExists("missing"))
+}
+
+func TestContextQueryParamsWithPrefix(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/?filter.name=x&filter.age=5&filter.age=6&filters.name=y&sort=name", nil)
+	e := New()
+	c := e.NewContext(req, nil)
+
+	testify.Equal(t, map[string][]string{
+		"name": {"x"},
+		"age":  {"5", "6"},
+	}, c.QueryParamsWithPrefix("filter."))
+	testify.Equal(t, map[string][]string{
+		".name":  {"x"},
+		".age":   {"5", "6"},
+		"s.name": {"y"},
+	}, c.QueryParamsWithPrefix("filter"))
+	testify.Empty(t, c.QueryParamsWithPrefix("page."))
+}
+
+func TestContextQueryParamKeys(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/?zeta=1&alpha=2&mid%20dle=3&zeta=4&empty&beta=", nil)
+	e := New()
+	c := e.NewContext(req, nil)
+	testify.Equal(t, []string{"zeta", "alpha", "mid dle", "empty", "beta"}, c.QueryParamKeys())
+
+	m := map[string][]string{}
+	if testify.NoError(t, c.Bind(&m)) {
+		testify.Equal(t, []string{"1", "4"}, m["zeta"])
+		for _, k := range c.QueryParamKeys() {
+			testify.Contains(t, m, k)
+		}
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	c = e.NewContext(req, nil)
+	testify.Empty(t, c.QueryParamKeys())
+}
+
 func TestContextFormFile(t *testing.T) {
 	e := New()
 	buf := new(bytes.Buffer)
@@ -572,6 +848,211 @@ func TestContextMultipartForm(t *testing.T) {
 	}
 }
 
+func TestContextMultipartReader(t *testing.T) {
+	e := New()
+	buf := new(bytes.Buffer)
+	mw := multipart.NewWriter(buf)
+	for name, content := range map[string]string{"a.txt": "first file", "b.txt": "second file"} {
+		w, err := mw.CreateFormFile("files", name)
+		if testify.NoError(t, err) {
+			w.Write([]byte(content))
+		}
+	}
+	mw.Close()
+	req := httptest.NewRequest(http.MethodPost, "/", buf)
+	req.Header.Set(HeaderContentType, mw.FormDataContentType())
+	c := e.NewContext(req, httptest.NewRecorder())
+
+	mr, err := c.MultipartReader()
+	if !testify.NoError(t, err) {
+		return
+	}
+	files := map[string]string{}
+	for {
+		p, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if !testify.NoError(t, err) {
+			return
+		}
+		b := new(bytes.Buffer)
+		_, err = io.Copy(b, p)
+		testify.NoError(t, err)
+		testify.Equal(t, "files", p.FormName())
+		files[p.FileName()] = b.String()
+	}
+	testify.Equal(t, map[string]string{"a.txt": "first file", "b.txt": "second file"}, files)
+
+	// Not a multipart request
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(userForm))
+	req.Header.Set(HeaderContentType, MIMEApplicationForm)
+	c = e.NewContext(req, httptest.NewRecorder())
+	_, err = c.MultipartReader()
+	testify.Error(t, err)
+}
+
+func TestContentDisposition(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"walle.png", `attachment; filename="walle.png"`},
+		{`say "hi"\.txt`, `attachment; filename="say \"hi\"\\.txt"`},
+		{"résumé 2020.pdf", `attachment; filename="r_sum_ 2020.pdf"; filename*=UTF-8''r%C3%A9sum%C3%A9%202020.pdf`},
+		{"日本.txt", `attachment; filename="__.txt"; filename*=UTF-8''%E6%97%A5%E6%9C%AC.txt`},
+	}
+	for _, tt := range tests {
+		testify.Equal(t, tt.expected, contentDisposition("attachment", tt.name))
+	}
+
+	e := New()
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+	if testify.NoError(t, c.Inline("_fixture/images/walle.png", "wallé.png")) {
+		testify.Equal(t, `inline; filename="wall_.png"; filename*=UTF-8''wall%C3%A9.png`, rec.Header().Get(HeaderContentDisposition))
+	}
+}
+
+type pushRecorder struct {
+	*httptest.ResponseRecorder
+	pushed []string
+}
+
+func (r *pushRecorder) Push(target string, opts *http.PushOptions) error {
+	r.pushed = append(r.pushed, target)
+	return nil
+}
+
+// upperJSONSerializer upper-cases the JSON it writes and the JSON it reads.
+type upperJSONSerializer struct{}
+
+func (upperJSONSerializer) Serialize(w io.Writer, i interface{}, indent string) error {
+	b, err := json.Marshal(i)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(bytes.ToUpper(b), '\n'))
+	return err
+}
+
+func (upperJSONSerializer) Deserialize(r io.Reader, i interface{}) error {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(bytes.ToUpper(b), i)
+}
+
+func TestContext_JSONSerializer(t *testing.T) {
+	e := New()
+	e.JSONSerializer = upperJSONSerializer{}
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+	if testify.NoError(t, c.JSON(http.StatusOK, Map{"name": "Jon"})) {
+		testify.Equal(t, MIMEApplicationJSONCharsetUTF8, rec.Header().Get(HeaderContentType))
+		testify.Equal(t, `{"NAME":"JON"}`+"\n", rec.Body.String())
+	}
+
+	e.DisableJSONTrailingNewline = true
+	rec = httptest.NewRecorder()
+	c = e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+	if testify.NoError(t, c.JSONP(http.StatusOK, "cb", Map{"name": "Jon"})) {
+		testify.Equal(t, `cb({"NAME":"JON"});`, rec.Body.String())
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"id":1,"name":"Jon"}`))
+	req.Header.Set(HeaderContentType, MIMEApplicationJSON)
+	c = e.NewContext(req, httptest.NewRecorder())
+	v := struct {
+		ID   int    `json:"ID"`
+		Name string `json:"NAME"`
+	}{}
+	if testify.NoError(t, c.Bind(&v)) {
+		testify.Equal(t, 1, v.ID)
+		testify.Equal(t, "JON", v.Name)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{`))
+	req.Header.Set(HeaderContentType, MIMEApplicationJSON)
+	c = e.NewContext(req, httptest.NewRecorder())
+	err := c.Bind(&v)
+	if testify.IsType(t, new(HTTPError), err) {
+		testify.Equal(t, http.StatusBadRequest, err.(*HTTPError).Code)
+	}
+}
+
+// flushCounter counts the flushes of a response, safely for concurrent use.
+type flushCounter struct {
+	*httptest.ResponseRecorder
+	mutex   sync.Mutex
+	flushes int
+}
+
+func (f *flushCounter) Flush() {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.flushes++
+}
+
+func (f *flushCounter) count() int {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.flushes
+}
+
+func TestContext_FlushEvery(t *testing.T) {
+	e := New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := &flushCounter{ResponseRecorder: httptest.NewRecorder()}
+	c := e.NewContext(req, rec)
+
+	stop := c.FlushEvery(20 * time.Millisecond)
+	testify.NoError(t, c.String(http.StatusOK, "a"))
+	c.Response().Write([]byte("b"))
+	testify.Equal(t, 0, rec.count())
+	time.Sleep(100 * time.Millisecond)
+	testify.Equal(t, 1, rec.count())
+
+	// Nothing written, nothing flushed
+	time.Sleep(50 * time.Millisecond)
+	testify.Equal(t, 1, rec.count())
+
+	c.Response().Write([]byte("c"))
+	stop()
+	testify.Equal(t, 2, rec.count())
+	testify.Equal(t, rec, c.Response().Writer)
+	time.Sleep(50 * time.Millisecond)
+	testify.Equal(t, 2, rec.count())
+	testify.Equal(t, "abc", rec.Body.String())
+
+	// Every write
+	rec = &flushCounter{ResponseRecorder: httptest.NewRecorder()}
+	c = e.NewContext(req, rec)
+	stop = c.FlushEvery(0)
+	c.Response().Write([]byte("a"))
+	c.Response().Write([]byte("b"))
+	testify.Equal(t, 2, rec.count())
+	stop()
+
+	// Not supported
+	c = e.NewContext(req, struct{ http.ResponseWriter }{httptest.NewRecorder()})
+	c.FlushEvery(time.Millisecond)()
+}
+
+func TestContext_Push(t *testing.T) {
+	e := New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	c := e.NewContext(req, httptest.NewRecorder())
+	testify.Equal(t, http.ErrNotSupported, c.Push("/app.css", nil))
+
+	rec := &pushRecorder{ResponseRecorder: httptest.NewRecorder()}
+	c = e.NewContext(req, rec)
+	testify.NoError(t, c.Push("/app.css", nil))
+	testify.NoError(t, c.Push("/app.js", &http.PushOptions{Method: http.MethodGet}))
+	testify.Equal(t, []string{"/app.css", "/app.js"}, rec.pushed)
+}
+
 func TestContextRedirect(t *testing.T) {
 	e := New()
 	req := httptest.NewRequest(http.MethodGet, "/", nil)
@@ -590,6 +1071,46 @@ func TestContextStore(t *testing.T) {
 	testify.Equal(t, "Jon Snow", c.Get("name"))
 }
 
+func TestContext_Keys(t *testing.T) {
+	e := New()
+	c := e.NewContext(nil, nil)
+	testify.Empty(t, c.Keys())
+
+	c.Set("user", "Jon Snow")
+	c.Set("id", 1)
+	testify.Equal(t, []string{"id", "user"}, c.Keys())
+
+	c.ResetStore()
+	testify.Empty(t, c.Keys())
+	testify.Nil(t, c.Get("user"))
+
+	// Pooled contexts are clean on reuse
+	e.GET("/", func(c Context) error {
+		keys := c.Keys()
+		c.Set("user", "Jon Snow")
+		return c.JSON(http.StatusOK, keys)
+	})
+	for i := 0; i < 2; i++ {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		testify.Equal(t, "[]\n", rec.Body.String())
+	}
+}
+
+func TestContext_SetContextValue(t *testing.T) {
+	e := New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	c := e.NewContext(req, httptest.NewRecorder())
+	c.SetContextValue("name", "Jon Snow")
+
+	testify.Equal(t, "Jon Snow", c.Get("name"))
+	ctx, cancel := stdContext.WithCancel(c.Request().Context())
+	defer cancel()
+	testify.Equal(t, "Jon Snow", FromContext(ctx, "name"))
+	testify.Nil(t, FromContext(ctx, "unknown"))
+	testify.Nil(t, FromContext(req.Context(), "name"))
+}
+
 func BenchmarkContext_Store(b *testing.B) {
 	e := &Echo{}
 
@@ -659,6 +1180,235 @@ func TestContext_Validate(t *testing.T) {
 	testify.NoError(t, c.Validate(struct{}{}))
 }
 
+type nameValidator struct{}
+
+func (*nameValidator) Validate(i interface{}) error {
+	if u, ok := i.(*user); ok && u.Name == "" {
+		return errors.New("name is required")
+	}
+	return nil
+}
+
+func TestContext_BindAndValidate(t *testing.T) {
+	e := New()
+	bind := func(body string) error {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set(HeaderContentType, MIMEApplicationJSON)
+		c := e.NewContext(req, httptest.NewRecorder())
+		return c.BindAndValidate(new(user))
+	}
+
+	testify.Equal(t, ErrValidatorNotRegistered, bind(userJSON))
+
+	e.Validator = &nameValidator{}
+	testify.NoError(t, bind(userJSON))
+
+	err := bind(`{"id":1}`)
+	if testify.IsType(t, new(HTTPError), err) {
+		testify.Equal(t, http.StatusUnprocessableEntity, err.(*HTTPError).Code)
+		testify.Equal(t, "name is required", err.(*HTTPError).Message)
+	}
+
+	// Bind errors are returned before validation
+	err = bind(`{"id":"1"}`)
+	if testify.IsType(t, new(HTTPError), err) {
+		testify.Equal(t, http.StatusBadRequest, err.(*HTTPError).Code)
+	}
+}
+
+type selfValidatedUser struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func (u *selfValidatedUser) Validate() error {
+	var errs FieldErrors
+	if u.ID <= 0 {
+		errs = append(errs, &FieldError{Field: "id", Source: "json", Message: "must be positive"})
+	}
+	if u.Name == "" {
+		errs = append(errs, &FieldError{Field: "name", Source: "json", Message: "is required"})
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func TestContext_ValidateOnBind(t *testing.T) {
+	e := New()
+	bind := func(body string) error {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set(HeaderContentType, MIMEApplicationJSON)
+		c := e.NewContext(req, httptest.NewRecorder())
+		return c.Bind(new(selfValidatedUser))
+	}
+
+	// Disabled
+	testify.NoError(t, bind(`{}`))
+
+	e.ValidateOnBind = true
+	testify.NoError(t, bind(userJSON))
+
+	err := bind(`{"id":0}`)
+	if testify.IsType(t, new(HTTPError), err) {
+		he := err.(*HTTPError)
+		testify.Equal(t, http.StatusUnprocessableEntity, he.Code)
+		errs := he.Message.(Map)["errors"].(FieldErrors)
+		if testify.Len(t, errs, 2) {
+			testify.Equal(t, "id", errs[0].Field)
+			testify.Equal(t, "name", errs[1].Field)
+		}
+	}
+
+	// Bind errors are returned before validation
+	err = bind(`{"id":"1"}`)
+	if testify.IsType(t, new(HTTPError), err) {
+		testify.Equal(t, http.StatusBadRequest, err.(*HTTPError).Code)
+	}
+
+	// Values without a Validate method
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{}`))
+	req.Header.Set(HeaderContentType, MIMEApplicationJSON)
+	testify.NoError(t, e.NewContext(req, httptest.NewRecorder()).Bind(new(user)))
+}
+
+func TestContext_BindSources(t *testing.T) {
+	type target struct {
+		ID      int    `param:"id" query:"id" json:"id"`
+		Name    string `query:"name" json:"name"`
+		Version string `header:"X-Version" query:"version"`
+	}
+	e := New()
+	req := httptest.NewRequest(http.MethodPost, "/?id=2&name=Query&version=q", strings.NewReader(`{"id":3,"name":"Body"}`))
+	req.Header.Set(HeaderContentType, MIMEApplicationJSON)
+	req.Header.Set("X-Version", "h")
+	c := e.NewContext(req, httptest.NewRecorder())
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+
+	v := new(target)
+	testify.NoError(t, c.BindQueryParams(v))
+	testify.Equal(t, target{ID: 2, Name: "Query", Version: "q"}, *v)
+
+	testify.NoError(t, c.BindHeaders(v))
+	testify.Equal(t, "h", v.Version)
+
+	testify.NoError(t, c.BindBody(v))
+	testify.Equal(t, 3, v.ID)
+	testify.Equal(t, "Body", v.Name)
+
+	// Path params bound last take precedence
+	testify.NoError(t, c.BindPathParams(v))
+	testify.Equal(t, 1, v.ID)
+
+	// Options of the binder apply
+	e.Binder = &DefaultBinder{CollectErrors: true}
+	c.SetParamValues("x")
+	err := c.BindPathParams(v)
+	if testify.IsType(t, new(HTTPError), err) {
+		testify.Equal(t, http.StatusBadRequest, err.(*HTTPError).Code)
+		testify.IsType(t, FieldErrors{}, err.(*HTTPError).Internal)
+	}
+}
+
+func TestContext_Negotiate(t *testing.T) {
+	e := New()
+	negotiate := func(accept string, offers ...string) (*httptest.ResponseRecorder, error) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if accept != "" {
+			req.Header.Set(HeaderAccept, accept)
+		}
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		return rec, c.Negotiate(http.StatusOK, user{1, "Jon Snow"}, offers...)
+	}
+
+	tests := []struct {
+		accept string
+		offers []string
+		ctype  string
+	}{
+		{"", nil, MIMEApplicationJSONCharsetUTF8},
+		{"application/json", nil, MIMEApplicationJSONCharsetUTF8},
+		{"application/xml", nil, MIMEApplicationXMLCharsetUTF8},
+		{"text/html", nil, MIMETextHTMLCharsetUTF8},
+		{"text/plain", nil, MIMETextPlainCharsetUTF8},
+		{"text/*", nil, MIMETextHTMLCharsetUTF8},
+		{"*/*", nil, MIMEApplicationJSONCharsetUTF8},
+		{"*/*", []string{MIMETextPlain, MIMEApplicationJSON}, MIMETextPlainCharsetUTF8},
+		{"application/json;q=0.5, application/xml", nil, MIMEApplicationXMLCharsetUTF8},
+		{"text/html, application/xhtml+xml, application/xml;q=0.9, */*;q=0.8", nil, MIMETextHTMLCharsetUTF8},
+		{"text/html;q=0, */*", []string{MIMETextHTML, MIMETextPlain}, MIMETextPlainCharsetUTF8},
+		{"Application/JSON", nil, MIMEApplicationJSONCharsetUTF8},
+	}
+	for _, tt := range tests {
+		rec, err := negotiate(tt.accept, tt.offers...)
+		if testify.NoError(t, err, tt.accept) {
+			testify.Equal(t, tt.ctype, rec.Header().Get(HeaderContentType), tt.accept)
+		}
+	}
+
+	rec, _ := negotiate("text/plain")
+	testify.Equal(t, "{1 Jon Snow}", rec.Body.String())
+	rec, _ = negotiate("text/html")
+	testify.Equal(t, "{1 Jon Snow}", rec.Body.String())
+
+	// No acceptable format
+	_, err := negotiate("image/png")
+	testify.Equal(t, ErrNotAcceptable, err)
+	_, err = negotiate("text/html", MIMEApplicationJSON)
+	testify.Equal(t, ErrNotAcceptable, err)
+
+}
+
+func TestContext_negotiateFormat(t *testing.T) {
+	e := New()
+	format := func(accept string, offers ...string) (string, error) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(HeaderAccept, accept)
+		c := e.NewContext(req, nil).(*context)
+		return c.negotiateFormat(offers)
+	}
+
+	f, err := format("application/json;q=0.5, application/xml")
+	if testify.NoError(t, err) {
+		testify.Equal(t, MIMEApplicationXML, f)
+	}
+
+	// No acceptable format
+	_, err = format("image/png")
+	testify.Equal(t, ErrNotAcceptable, err)
+	_, err = format("text/html", MIMEApplicationJSON)
+	testify.Equal(t, ErrNotAcceptable, err)
+
+	// Default format
+	e.DefaultFormat = MIMEApplicationJSON
+	f, err = format("image/png")
+	if testify.NoError(t, err) {
+		testify.Equal(t, MIMEApplicationJSON, f)
+	}
+	f, err = format("text/html", MIMETextPlain)
+	if testify.NoError(t, err) {
+		testify.Equal(t, MIMEApplicationJSON, f)
+	}
+}
+
+func TestContext_AcceptsLanguages(t *testing.T) {
+	e := New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(HeaderAcceptLanguage, "de;q=0.7, en-US, fr;q=0, en;q=0.9")
+	req.Header.Set(HeaderAcceptEncoding, "gzip;q=0.5, br, identity;q=0")
+	c := e.NewContext(req, nil)
+
+	testify.Equal(t, []string{"en-US", "en", "de"}, c.AcceptsLanguages())
+	testify.Equal(t, []string{"br", "gzip"}, c.AcceptsEncodings())
+
+	c = e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), nil)
+	testify.Empty(t, c.AcceptsLanguages())
+	testify.Empty(t, c.AcceptsEncodings())
+}
+
 func TestContext_QueryString(t *testing.T) {
 	e := New()
 
@@ -784,6 +1534,33 @@ func TestContext_IsWebSocket(t *testing.T) {
 	}
 }
 
+func TestContext_WebSocketSubprotocol(t *testing.T) {
+	tests := []struct {
+		name      string
+		requested []string
+		expected  string
+	}{
+		{"match", []string{"v1.chat, v2.chat"}, "v2.chat"},
+		{"match in second header", []string{"v1.chat", "v3.chat,v2.chat"}, "v3.chat"},
+		{"no match", []string{"v1.chat"}, ""},
+		{"empty client list", nil, ""},
+	}
+
+	e := New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(HeaderUpgrade, "websocket")
+			req.Header[http.CanonicalHeaderKey(HeaderSecWebSocketProtocol)] = tt.requested
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			testify.Equal(t, tt.expected, c.WebSocketSubprotocol("v3.chat", "v2.chat"))
+			testify.Equal(t, tt.expected, rec.Header().Get(HeaderSecWebSocketProtocol))
+		})
+	}
+}
+
 func TestContext_Bind(t *testing.T) {
 	e := New()
 	req := httptest.NewRequest(POST, "/", strings.NewReader(userJSON))
@@ -840,3 +1617,40 @@ func TestContext_RealIP(t *testing.T) {
 		testify.Equal(t, tt.s, tt.c.RealIP())
 	}
 }
+
+func TestContext_SetRealIP(t *testing.T) {
+	e := New()
+	var seen []string
+	e.Use(func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			if ip := c.Request().Header.Get("X-Client-IP"); ip != "" {
+				c.SetRealIP(ip)
+			}
+			return next(c)
+		}
+	}, func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			seen = append(seen, c.RealIP())
+			return next(c)
+		}
+	})
+	e.GET("/", func(c Context) error {
+		return c.String(http.StatusOK, c.RealIP())
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(HeaderXRealIP, "192.168.0.1")
+	req.Header.Set("X-Client-IP", "10.0.0.1")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	testify.Equal(t, "10.0.0.1", rec.Body.String())
+
+	// Override doesn't leak into the next request using a pooled context
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(HeaderXRealIP, "192.168.0.1")
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	testify.Equal(t, "192.168.0.1", rec.Body.String())
+
+	testify.Equal(t, []string{"10.0.0.1", "192.168.0.1"}, seen)
+}
@@ -42,17 +42,23 @@ import (
 	"crypto/tls"
 	"errors"
 	"fmt"
+	"html"
 	"io"
 	"io/ioutil"
 	stdLog "log"
 	"net"
 	"net/http"
 	"net/url"
+	"os"
+	"os/signal"
 	"path"
 	"path/filepath"
 	"reflect"
 	"runtime"
+	"strconv"
+	"strings"
 	"sync"
+	"syscall"
 	"time"
 
 	"github.com/labstack/gommon/color"
@@ -73,7 +79,10 @@ type (
 		router           *Router
 		routers          map[string]*Router
 		notFoundHandler  HandlerFunc
+		errorMappings    []errorMapping
 		pool             sync.Pool
+		startupMutex     sync.RWMutex
+		listeners        []net.Listener
 		Server           *http.Server
 		TLSServer        *http.Server
 		Listener         net.Listener
@@ -88,6 +97,54 @@ type (
 		Validator        Validator
 		Renderer         Renderer
 		Logger           Logger
+		// JSON responses escape `<`, `>` and `&` and end with a newline by
+		// default, see `json.Encoder`. These options turn either off.
+		DisableJSONHTMLEscape      bool
+		DisableJSONTrailingNewline bool
+		// CookieSigningKeys are the keys used by `Context#SetCookieSigned()` and
+		// `Context#GetCookieSigned()`. The first key signs new cookies while all
+		// of them are accepted when verifying, which allows rotating keys.
+		CookieSigningKeys [][]byte
+		// MaxResponseSize caps the size in bytes of responses serialized by
+		// `Context#JSON()`, `Context#JSONP()` and `Context#XML()` and their
+		// variants. Larger responses are aborted before anything is sent and
+		// logged, and the handler returns a 500 error. Zero means no limit.
+		MaxResponseSize int64
+		// WebSocketCheckOrigin reports whether `Context#WebSocket()` accepts
+		// the handshake of the request, usually based on its `Origin` header.
+		// If nil, only requests without `Origin` header or with one matching
+		// the host of the request are accepted, which prevents cross-site
+		// WebSocket hijacking.
+		WebSocketCheckOrigin func(c Context) bool
+		// ValidateOnBind makes `Context#Bind()` call the `Validate() error`
+		// method of bound values implementing it, turning its error into a
+		// "422 - Unprocessable Entity" error.
+		ValidateOnBind bool
+		// DefaultFormat is the MIME type `Context#Negotiate()` responds with
+		// when none of the offered types is acceptable to the client, e.g.
+		// `application/json`. If empty, "406 - Not Acceptable" error is
+		// returned instead.
+		DefaultFormat string
+		// JSONSerializer replaces encoding/json for JSON responses and for
+		// binding JSON request bodies, e.g. with a faster implementation. The
+		// `DisableJSONHTMLEscape` option only applies to encoding/json.
+		JSONSerializer JSONSerializer
+		// TrailingSlash defines whether routes added afterwards also match
+		// their path with a trailing slash added or removed, see
+		// `TrailingSlashPolicy`. Groups may override it.
+		TrailingSlash TrailingSlashPolicy
+		// DisableAutoOptions disables answering OPTIONS requests for paths
+		// without an OPTIONS route with "204 - No Content" and the allowed
+		// methods in the Allow header. They get "405 - Method Not Allowed"
+		// instead.
+		DisableAutoOptions bool
+		// CaseInsensitivePaths makes routes added afterwards match request
+		// paths regardless of the case of their static parts.
+		CaseInsensitivePaths bool
+		// RedirectCaseInsensitivePaths makes requests matched because of
+		// `CaseInsensitivePaths` permanently redirect to the path with the
+		// case of the route instead.
+		RedirectCaseInsensitivePaths bool
 	}
 
 	// Route contains a handler and information for matching against requests.
@@ -95,6 +152,15 @@ type (
 		Method string `json:"method"`
 		Path   string `json:"path"`
 		Name   string `json:"name"`
+		// Handler identifies the route's handler by its function name, even
+		// if the route was renamed.
+		Handler string `json:"handler"`
+
+		// Meta holds arbitrary route metadata, e.g. `r.Meta["auth"] = "required"`.
+		// It is available to middleware during a request via `Context#RouteMeta()`,
+		// so a single middleware can branch on it instead of attaching
+		// different middleware per route.
+		Meta map[string]interface{} `json:"-"`
 	}
 
 	// HTTPError represents an error that occurred while handling a request.
@@ -118,6 +184,16 @@ type (
 		Validate(i interface{}) error
 	}
 
+	// JSONSerializer is the interface that encodes values to JSON and decodes
+	// them from JSON, see `Echo#JSONSerializer`.
+	JSONSerializer interface {
+		// Serialize writes the JSON encoding of i to w, indented with indent
+		// unless it's empty.
+		Serialize(w io.Writer, i interface{}, indent string) error
+		// Deserialize decodes the JSON value read from r into i.
+		Deserialize(r io.Reader, i interface{}) error
+	}
+
 	// Renderer is the interface that wraps the Render function.
 	Renderer interface {
 		Render(io.Writer, string, interface{}, Context) error
@@ -126,10 +202,32 @@ type (
 	// Map defines a generic map of type `map[string]interface{}`.
 	Map map[string]interface{}
 
+	// errorMapping associates a sentinel error with an HTTP status code.
+	errorMapping struct {
+		err  error
+		code int
+	}
+
+	// TrailingSlashPolicy defines how requests for the path of a route with a
+	// trailing slash added or removed, e.g. `/foo/` for `/foo`, are handled.
+	TrailingSlashPolicy uint8
+
 	// Common struct for Echo & Group.
 	common struct{}
 )
 
+// Trailing slash policies
+const (
+	// TrailingSlashStrict treats `/foo` and `/foo/` as different paths. It's
+	// the default.
+	TrailingSlashStrict TrailingSlashPolicy = iota + 1
+	// TrailingSlashMatch serves both paths with the route's handler.
+	TrailingSlashMatch
+	// TrailingSlashRedirect permanently redirects the other path to the
+	// route's one, with 301 for GET and HEAD requests and 308 otherwise.
+	TrailingSlashRedirect
+)
+
 // HTTP methods
 // NOTE: Deprecated, please use the stdlib constants directly instead.
 const (
@@ -149,6 +247,7 @@ const (
 const (
 	MIMEApplicationJSON                  = "application/json"
 	MIMEApplicationJSONCharsetUTF8       = MIMEApplicationJSON + "; " + charsetUTF8
+	MIMEApplicationProblemJSON           = "application/problem+json"
 	MIMEApplicationJavaScript            = "application/javascript"
 	MIMEApplicationJavaScriptCharsetUTF8 = MIMEApplicationJavaScript + "; " + charsetUTF8
 	MIMEApplicationXML                   = "application/xml"
@@ -164,6 +263,7 @@ const (
 	MIMETextPlainCharsetUTF8             = MIMETextPlain + "; " + charsetUTF8
 	MIMEMultipartForm                    = "multipart/form-data"
 	MIMEOctetStream                      = "application/octet-stream"
+	MIMETextEventStream                  = "text/event-stream"
 )
 
 const (
@@ -178,16 +278,25 @@ const (
 const (
 	HeaderAccept              = "Accept"
 	HeaderAcceptEncoding      = "Accept-Encoding"
+	HeaderAcceptLanguage      = "Accept-Language"
+	HeaderAge                 = "Age"
 	HeaderAllow               = "Allow"
 	HeaderAuthorization       = "Authorization"
+	HeaderCacheControl        = "Cache-Control"
+	HeaderConnection          = "Connection"
 	HeaderContentDisposition  = "Content-Disposition"
 	HeaderContentEncoding     = "Content-Encoding"
 	HeaderContentLength       = "Content-Length"
 	HeaderContentType         = "Content-Type"
 	HeaderCookie              = "Cookie"
+	HeaderDeprecation         = "Deprecation"
 	HeaderSetCookie           = "Set-Cookie"
+	HeaderTransferEncoding    = "Transfer-Encoding"
+	HeaderETag                = "ETag"
 	HeaderIfModifiedSince     = "If-Modified-Since"
+	HeaderIfNoneMatch         = "If-None-Match"
 	HeaderLastModified        = "Last-Modified"
+	HeaderLink                = "Link"
 	HeaderLocation            = "Location"
 	HeaderUpgrade             = "Upgrade"
 	HeaderVary                = "Vary"
@@ -202,8 +311,15 @@ const (
 	HeaderXRequestID          = "X-Request-ID"
 	HeaderXRequestedWith      = "X-Requested-With"
 	HeaderServer              = "Server"
+	HeaderSunset              = "Sunset"
 	HeaderOrigin              = "Origin"
 
+	// WebSocket
+	HeaderSecWebSocketAccept   = "Sec-WebSocket-Accept"
+	HeaderSecWebSocketKey      = "Sec-WebSocket-Key"
+	HeaderSecWebSocketProtocol = "Sec-WebSocket-Protocol"
+	HeaderSecWebSocketVersion  = "Sec-WebSocket-Version"
+
 	// Access control
 	HeaderAccessControlRequestMethod    = "Access-Control-Request-Method"
 	HeaderAccessControlRequestHeaders   = "Access-Control-Request-Headers"
@@ -229,6 +345,11 @@ const (
 	// Version of Echo
 	Version = "4.1.11"
 	website = "https://echo.labstack.com"
+	// unixAddressPrefix marks addresses of Unix domain sockets
+	unixAddressPrefix = "unix:"
+	// systemdFirstFD is the first file descriptor passed by systemd socket
+	// activation
+	systemdFirstFD = 3
 	// http://patorjk.com/software/taag/#p=display&f=Small%20Slant&t=Echo
 	banner = `
    ____    __
@@ -265,6 +386,7 @@ var (
 	ErrUnauthorized                = NewHTTPError(http.StatusUnauthorized)
 	ErrForbidden                   = NewHTTPError(http.StatusForbidden)
 	ErrMethodNotAllowed            = NewHTTPError(http.StatusMethodNotAllowed)
+	ErrNotAcceptable               = NewHTTPError(http.StatusNotAcceptable)
 	ErrStatusRequestEntityTooLarge = NewHTTPError(http.StatusRequestEntityTooLarge)
 	ErrTooManyRequests             = NewHTTPError(http.StatusTooManyRequests)
 	ErrBadRequest                  = NewHTTPError(http.StatusBadRequest)
@@ -276,6 +398,10 @@ var (
 	ErrRendererNotRegistered       = errors.New("renderer not registered")
 	ErrInvalidRedirectCode         = errors.New("invalid redirect status code")
 	ErrCookieNotFound              = errors.New("cookie not found")
+	ErrCookieInvalidSignature      = errors.New("invalid cookie signature")
+	ErrCookieExpired               = errors.New("cookie expired")
+	ErrCookieSigningKeyNotSet      = errors.New("cookie signing key not set")
+	ErrResponseTooLarge            = errors.New("response exceeds maximum size")
 	ErrInvalidCertOrKeyType        = errors.New("invalid cert or key type, must be string or []byte")
 )
 
@@ -286,6 +412,9 @@ var (
 	}
 
 	MethodNotAllowedHandler = func(c Context) error {
+		if allow := c.matched().allow; allow != "" {
+			c.Response().Header().Set(HeaderAllow, allow)
+		}
 		return ErrMethodNotAllowed
 	}
 )
@@ -338,6 +467,25 @@ func (e *Echo) Routers() map[string]*Router {
 	return e.routers
 }
 
+// RegisterErrorMapping maps a sentinel error to an HTTP status code. Errors
+// returned by handlers are matched against registered mappings using
+// `errors.Is()` in the order they were registered, and the default HTTP error
+// handler responds with the mapped status code and the sentinel error message.
+func (e *Echo) RegisterErrorMapping(err error, code int) {
+	e.errorMappings = append(e.errorMappings, errorMapping{err: err, code: code})
+}
+
+// mappedHTTPError returns an HTTPError for the first registered mapping which
+// matches err, or nil if none does.
+func (e *Echo) mappedHTTPError(err error) *HTTPError {
+	for _, m := range e.errorMappings {
+		if errors.Is(err, m.err) {
+			return NewHTTPError(m.code, m.err.Error()).SetInternal(err)
+		}
+	}
+	return nil
+}
+
 // DefaultHTTPErrorHandler is the default HTTP error handler. It sends a JSON response
 // with status code.
 func (e *Echo) DefaultHTTPErrorHandler(err error, c Context) {
@@ -348,7 +496,7 @@ func (e *Echo) DefaultHTTPErrorHandler(err error, c Context) {
 				he = herr
 			}
 		}
-	} else {
+	} else if he = e.mappedHTTPError(err); he == nil {
 		he = &HTTPError{
 			Code:    http.StatusInternalServerError,
 			Message: http.StatusText(http.StatusInternalServerError),
@@ -364,6 +512,8 @@ func (e *Echo) DefaultHTTPErrorHandler(err error, c Context) {
 	if !c.Response().Committed {
 		if c.Request().Method == http.MethodHead { // Issue #608
 			err = c.NoContent(he.Code)
+		} else if acceptsHTML(c.Request()) {
+			err = c.HTML(he.Code, errorPage(he.Code, he.Message))
 		} else {
 			err = c.JSON(he.Code, he.Message)
 		}
@@ -373,6 +523,26 @@ func (e *Echo) DefaultHTTPErrorHandler(err error, c Context) {
 	}
 }
 
+// acceptsHTML reports whether the client prefers HTML to JSON, like browsers.
+func acceptsHTML(r *http.Request) bool {
+	accept := r.Header.Get(HeaderAccept)
+	return strings.Contains(accept, MIMETextHTML) && !strings.Contains(accept, MIMEApplicationJSON)
+}
+
+// errorPage renders an HTML error page with the error message.
+func errorPage(code int, message interface{}) string {
+	if m, ok := message.(Map); ok && m["message"] != nil {
+		message = m["message"]
+	}
+	title := html.EscapeString(fmt.Sprintf("%d %s", code, http.StatusText(code)))
+	msg := ""
+	if message != nil {
+		msg = html.EscapeString(fmt.Sprint(message))
+	}
+	return "<!DOCTYPE html>\n<html><head><title>" + title + "</title></head>" +
+		"<body><h1>" + title + "</h1><pre>" + msg + "</pre></body></html>\n"
+}
+
 // Pre adds middleware to the chain which is run before router.
 func (e *Echo) Pre(middleware ...MiddlewareFunc) {
 	e.premiddleware = append(e.premiddleware, middleware...)
@@ -493,30 +663,103 @@ func (e *Echo) File(path, file string, m ...MiddlewareFunc) *Route {
 	return e.file(path, file, e.GET, m...)
 }
 
-func (e *Echo) add(host, method, path string, handler HandlerFunc, middleware ...MiddlewareFunc) *Route {
+func (e *Echo) add(host, method, path string, slash TrailingSlashPolicy, handler HandlerFunc, middleware ...MiddlewareFunc) *Route {
 	name := handlerName(handler)
 	router := e.findRouter(host)
-	router.Add(method, path, func(c Context) error {
+	path, constraints := parseRouteConstraints(path)
+	router.addConstraints(method, path, constraints)
+	checkConstraints := func(c Context) error {
+		for _, rc := range constraints {
+			if !rc.match(c.Param(rc.pname)) {
+				return ErrNotFound
+			}
+		}
+		return nil
+	}
+	h := func(c Context) error {
+		if err := checkConstraints(c); err != nil {
+			return err
+		}
 		h := handler
 		// Chain middleware
 		for i := len(middleware) - 1; i >= 0; i-- {
 			h = middleware[i](h)
 		}
 		return h(c)
-	})
+	}
+	router.Add(method, path, h)
+
+	// Routes added for the other path are replaced by explicitly added ones
+	// and don't replace them.
+	if alt := trailingSlashAlternate(path); alt != "" && e.router.routes[method+alt] == nil {
+		switch slash {
+		case TrailingSlashMatch:
+			router.Add(method, alt, func(c Context) error {
+				c.SetPath(path)
+				return h(c)
+			})
+		case TrailingSlashRedirect:
+			router.Add(method, alt, func(c Context) error {
+				if err := checkConstraints(c); err != nil {
+					return err
+				}
+				return redirectTrailingSlash(c)
+			})
+		}
+	}
+
 	r := &Route{
-		Method: method,
-		Path:   path,
-		Name:   name,
+		Method:  method,
+		Path:    path,
+		Name:    name,
+		Handler: name,
+		Meta:    map[string]interface{}{},
 	}
 	e.router.routes[method+path] = r
 	return r
 }
 
 // Add registers a new route for an HTTP method and path with matching handler
-// in the router with optional route-level middleware.
+// in the router with optional route-level middleware. Path parameters may be
+// constrained, e.g. `/users/:id<int>` or `/files/:name<regex([a-z]+\.png)>`;
+// requests with non-matching values get a 404 before the handler runs. It panics
+// if a constrained route has other param names or constraints than a route
+// registered before for the same path.
 func (e *Echo) Add(method, path string, handler HandlerFunc, middleware ...MiddlewareFunc) *Route {
-	return e.add("", method, path, handler, middleware...)
+	return e.add("", method, path, e.TrailingSlash, handler, middleware...)
+}
+
+// trailingSlashAlternate returns path with the trailing slash added or
+// removed, or an empty string for the root and wildcard paths.
+func trailingSlashAlternate(path string) string {
+	l := len(path)
+	switch {
+	case l <= 1 || path[l-1] == '*':
+		return ""
+	case path[l-1] == '/':
+		return path[:l-1]
+	}
+	return path + "/"
+}
+
+// redirectTrailingSlash redirects the request to its path with the trailing
+// slash added or removed, keeping the query string.
+func redirectTrailingSlash(c Context) error {
+	return redirectPermanently(c, trailingSlashAlternate(c.Request().URL.Path))
+}
+
+// redirectPermanently redirects the request to path, keeping the query string,
+// with 301 for GET and HEAD requests and 308 otherwise.
+func redirectPermanently(c Context, path string) error {
+	req := c.Request()
+	if qs := req.URL.RawQuery; qs != "" {
+		path += "?" + qs
+	}
+	code := http.StatusPermanentRedirect
+	if req.Method == http.MethodGet || req.Method == http.MethodHead {
+		code = http.StatusMovedPermanently
+	}
+	return c.Redirect(code, path)
 }
 
 // Host creates a new router group for the provided host and optional host-level middleware.
@@ -621,7 +864,9 @@ func (e *Echo) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	e.pool.Put(c)
 }
 
-// Start starts an HTTP server.
+// Start starts an HTTP server. Addresses prefixed with "unix:", e.g.
+// "unix:/run/app.sock", are Unix domain socket paths. To serve on another
+// listener, e.g. from `SystemdListeners()`, set `Echo#Listener` instead.
 func (e *Echo) Start(address string) error {
 	e.Server.Addr = address
 	return e.StartServer(e.Server)
@@ -680,19 +925,68 @@ func (e *Echo) startTLS(address string) error {
 	return e.StartServer(e.TLSServer)
 }
 
-// StartServer starts a custom http server.
-func (e *Echo) StartServer(s *http.Server) (err error) {
-	// Setup
-	e.colorer.SetOutput(e.Logger.Output())
-	s.ErrorLog = e.StdLogger
-	s.Handler = e
-	if e.Debug {
-		e.Logger.SetLevel(log.DEBUG)
+// StartServers starts an HTTP server listening on all of the addresses, e.g. on
+// both an internal and an external interface. If any of the addresses can't be
+// listened on, the server isn't started and all the errors are returned.
+// `Echo#Shutdown()` and `Echo#Close()` stop serving on all of the addresses.
+func (e *Echo) StartServers(addresses ...string) error {
+	if len(addresses) == 0 {
+		return errors.New("echo: no addresses to start servers on")
+	}
+	var errs []string
+	listeners := make([]net.Listener, 0, len(addresses))
+	for _, address := range addresses {
+		l, err := newListener(address)
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		listeners = append(listeners, l)
+	}
+	if len(errs) > 0 {
+		for _, l := range listeners {
+			l.Close()
+		}
+		return errors.New(strings.Join(errs, "; "))
+	}
+	e.startupMutex.Lock()
+	e.listeners = listeners
+	e.startupMutex.Unlock()
+
+	s := e.Server
+	e.setupServer(s)
+	errCh := make(chan error, len(listeners))
+	for _, l := range listeners {
+		if !e.HidePort {
+			e.colorer.Printf("⇨ http server started on %s\n", e.colorer.Green(l.Addr()))
+		}
+		go func(l net.Listener) {
+			errCh <- s.Serve(l)
+		}(l)
 	}
 
-	if !e.HideBanner {
-		e.colorer.Printf(banner, e.colorer.Red("v"+Version), e.colorer.Blue(website))
+	// Stop serving on all of the listeners once any of them fails
+	err := <-errCh
+	if err != http.ErrServerClosed {
+		s.Close()
+	}
+	for range listeners[1:] {
+		<-errCh
 	}
+	return err
+}
+
+// Listeners returns the listeners of the server started by
+// `Echo#StartServers()`, or nil if it wasn't started yet.
+func (e *Echo) Listeners() []net.Listener {
+	e.startupMutex.RLock()
+	defer e.startupMutex.RUnlock()
+	return append([]net.Listener(nil), e.listeners...)
+}
+
+// StartServer starts a custom http server.
+func (e *Echo) StartServer(s *http.Server) (err error) {
+	e.setupServer(s)
 
 	if s.TLSConfig == nil {
 		if e.Listener == nil {
@@ -719,6 +1013,19 @@ func (e *Echo) StartServer(s *http.Server) (err error) {
 	return s.Serve(e.TLSListener)
 }
 
+func (e *Echo) setupServer(s *http.Server) {
+	e.colorer.SetOutput(e.Logger.Output())
+	s.ErrorLog = e.StdLogger
+	s.Handler = e
+	if e.Debug {
+		e.Logger.SetLevel(log.DEBUG)
+	}
+
+	if !e.HideBanner {
+		e.colorer.Printf(banner, e.colorer.Red("v"+Version), e.colorer.Blue(website))
+	}
+}
+
 // Close immediately stops the server.
 // It internally calls `http.Server#Close()`.
 func (e *Echo) Close() error {
@@ -737,6 +1044,35 @@ func (e *Echo) Shutdown(ctx stdContext.Context) error {
 	return e.Server.Shutdown(ctx)
 }
 
+// ShutdownOnSignal shuts the server down gracefully with `Echo#Shutdown()`
+// once one of signals, by default SIGINT and SIGTERM, is received, waiting at
+// most timeout for in-flight requests. The returned channel receives the
+// result of the shutdown. Usage:
+//
+//  done := e.ShutdownOnSignal(10 * time.Second)
+//  if err := e.Start(":1323"); err != http.ErrServerClosed {
+//  	e.Logger.Fatal(err)
+//  }
+//  if err := <-done; err != nil {
+//  	e.Logger.Fatal(err)
+//  }
+func (e *Echo) ShutdownOnSignal(timeout time.Duration, signals ...os.Signal) <-chan error {
+	if len(signals) == 0 {
+		signals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+	}
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, signals...)
+	done := make(chan error, 1)
+	go func() {
+		<-quit
+		signal.Stop(quit)
+		ctx, cancel := stdContext.WithTimeout(stdContext.Background(), timeout)
+		defer cancel()
+		done <- e.Shutdown(ctx)
+	}()
+	return done
+}
+
 // NewHTTPError creates a new HTTPError instance.
 func NewHTTPError(code int, message ...interface{}) *HTTPError {
 	he := &HTTPError{Code: code, Message: http.StatusText(code)}
@@ -757,6 +1093,18 @@ func (he *HTTPError) SetInternal(err error) *HTTPError {
 	return he
 }
 
+// WithInternal returns a copy of the HTTPError with err as internal error.
+// Unlike `SetInternal()` it leaves shared errors like `ErrNotFound` unchanged.
+func (he *HTTPError) WithInternal(err error) *HTTPError {
+	return &HTTPError{Code: he.Code, Message: he.Message, Internal: err}
+}
+
+// Unwrap returns the internal error, so `errors.As()` finds errors such as
+// `*BindingError` in HTTPErrors.
+func (he *HTTPError) Unwrap() error {
+	return he.Internal
+}
+
 // WrapHandler wraps `http.Handler` into `echo.HandlerFunc`.
 func WrapHandler(h http.Handler) HandlerFunc {
 	return func(c Context) error {
@@ -828,7 +1176,10 @@ func (ln tcpKeepAliveListener) Accept() (c net.Conn, err error) {
 	return
 }
 
-func newListener(address string) (*tcpKeepAliveListener, error) {
+func newListener(address string) (net.Listener, error) {
+	if strings.HasPrefix(address, unixAddressPrefix) {
+		return net.Listen("unix", address[len(unixAddressPrefix):])
+	}
 	l, err := net.Listen("tcp", address)
 	if err != nil {
 		return nil, err
@@ -836,6 +1187,47 @@ func newListener(address string) (*tcpKeepAliveListener, error) {
 	return &tcpKeepAliveListener{l.(*net.TCPListener)}, nil
 }
 
+// SystemdListeners returns the listeners passed by systemd socket activation,
+// in the order of the socket unit, or none if the process wasn't socket
+// activated. Usage:
+//
+//  listeners, err := echo.SystemdListeners()
+//  if err == nil && len(listeners) > 0 {
+//  	e.Listener = listeners[0]
+//  }
+func SystemdListeners() ([]net.Listener, error) {
+	return systemdListeners(systemdFirstFD)
+}
+
+func systemdListeners(firstFD int) ([]net.Listener, error) {
+	if os.Getenv("LISTEN_PID") != strconv.Itoa(os.Getpid()) {
+		return nil, nil
+	}
+	n, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || n < 1 {
+		return nil, err
+	}
+	// Keeps child processes from using the listeners as well
+	os.Unsetenv("LISTEN_PID")
+	os.Unsetenv("LISTEN_FDS")
+	os.Unsetenv("LISTEN_FDNAMES")
+
+	listeners := make([]net.Listener, 0, n)
+	for fd := firstFD; fd < firstFD+n; fd++ {
+		f := os.NewFile(uintptr(fd), "LISTEN_FD_"+strconv.Itoa(fd))
+		l, err := net.FileListener(f)
+		f.Close()
+		if err != nil {
+			for _, l := range listeners {
+				l.Close()
+			}
+			return nil, err
+		}
+		listeners = append(listeners, l)
+	}
+	return listeners, nil
+}
+
 func applyMiddleware(h HandlerFunc, middleware ...MiddlewareFunc) HandlerFunc {
 	for i := len(middleware) - 1; i >= 0; i-- {
 		h = middleware[i](h)
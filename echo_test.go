@@ -4,10 +4,15 @@ import (
 	"bytes"
 	stdContext "context"
 	"errors"
+	"fmt"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"reflect"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -98,6 +103,47 @@ func TestEchoFile(t *testing.T) {
 	assert.NotEmpty(t, b)
 }
 
+func TestEchoFileRange(t *testing.T) {
+	e := New()
+	e.File("/index", "_fixture/index.html")
+	req := httptest.NewRequest(http.MethodGet, "/index", nil)
+	req.Header.Set("Range", "bytes=0-8")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusPartialContent, rec.Code)
+	assert.Equal(t, "<!doctype", rec.Body.String())
+	assert.Equal(t, "bytes", rec.Header().Get("Accept-Ranges"))
+	assert.True(t, strings.HasPrefix(rec.Header().Get("Content-Range"), "bytes 0-8/"))
+
+	req.Header.Set("Range", "bytes=100000-")
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusRequestedRangeNotSatisfiable, rec.Code)
+}
+
+func TestEchoFileConditional(t *testing.T) {
+	e := New()
+	e.File("/walle", "_fixture/images/walle.png")
+	req := httptest.NewRequest(http.MethodGet, "/walle", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	etag := rec.Header().Get(HeaderETag)
+	assert.NotEmpty(t, etag)
+	assert.NotEmpty(t, rec.Header().Get(HeaderLastModified))
+
+	req = httptest.NewRequest(http.MethodGet, "/walle", nil)
+	req.Header.Set(HeaderIfNoneMatch, etag)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/walle", nil)
+	req.Header.Set(HeaderIfModifiedSince, time.Now().UTC().Format(http.TimeFormat))
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+}
+
 func TestEchoMiddleware(t *testing.T) {
 	e := New()
 	buf := new(bytes.Buffer)
@@ -286,10 +332,10 @@ func TestEchoURL(t *testing.T) {
 func TestEchoRoutes(t *testing.T) {
 	e := New()
 	routes := []*Route{
-		{http.MethodGet, "/users/:user/events", ""},
-		{http.MethodGet, "/users/:user/events/public", ""},
-		{http.MethodPost, "/repos/:owner/:repo/git/refs", ""},
-		{http.MethodPost, "/repos/:owner/:repo/git/tags", ""},
+		{Method: http.MethodGet, Path: "/users/:user/events", Name: ""},
+		{Method: http.MethodGet, Path: "/users/:user/events/public", Name: ""},
+		{Method: http.MethodPost, Path: "/repos/:owner/:repo/git/refs", Name: ""},
+		{Method: http.MethodPost, Path: "/repos/:owner/:repo/git/tags", Name: ""},
 	}
 	for _, r := range routes {
 		e.Add(r.Method, r.Path, func(c Context) error {
@@ -313,6 +359,57 @@ func TestEchoRoutes(t *testing.T) {
 	}
 }
 
+func listUsers(c Context) error {
+	return c.String(http.StatusOK, "OK")
+}
+
+func TestEchoRoutesHandler(t *testing.T) {
+	e := New()
+	e.GET("/users", listUsers).Name = "users"
+	routes := e.Routes()
+	if assert.Len(t, routes, 1) {
+		assert.Equal(t, "users", routes[0].Name)
+		assert.Equal(t, "github.com/labstack/echo/v4.listUsers", routes[0].Handler)
+	}
+}
+
+func TestEchoRouteMeta(t *testing.T) {
+	e := New()
+	e.Use(func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			if c.RouteMeta()["auth"] == "required" && c.Request().Header.Get(HeaderAuthorization) == "" {
+				return ErrUnauthorized
+			}
+			return next(c)
+		}
+	})
+	e.GET("/public", func(c Context) error {
+		return c.String(http.StatusOK, "public")
+	})
+	r := e.GET("/private/:id", func(c Context) error {
+		return c.String(http.StatusOK, "private")
+	})
+	r.Meta["auth"] = "required"
+
+	c, b := request(http.MethodGet, "/public", e)
+	assert.Equal(t, http.StatusOK, c)
+	assert.Equal(t, "public", b)
+
+	c, _ = request(http.MethodGet, "/private/1", e)
+	assert.Equal(t, http.StatusUnauthorized, c)
+
+	req := httptest.NewRequest(http.MethodGet, "/private/1", nil)
+	req.Header.Set(HeaderAuthorization, "Bearer token")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "private", rec.Body.String())
+
+	// No matching route
+	c, _ = request(http.MethodGet, "/unknown", e)
+	assert.Equal(t, http.StatusNotFound, c)
+}
+
 func TestEchoEncodedPath(t *testing.T) {
 	e := New()
 	e.GET("/:id", func(c Context) error {
@@ -324,6 +421,78 @@ func TestEchoEncodedPath(t *testing.T) {
 	assert.Equal(t, http.StatusOK, rec.Code)
 }
 
+func TestEchoHost(t *testing.T) {
+	e := New()
+	okHandler := func(c Context) error { return c.String(http.StatusOK, http.StatusText(http.StatusOK)) }
+	e.GET("/", func(c Context) error { return c.String(http.StatusOK, "default") })
+
+	api := e.Host("api.example.com", func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			c.Response().Header().Set("X-Host", "api")
+			return next(c)
+		}
+	})
+	api.GET("/", func(c Context) error { return c.String(http.StatusOK, "api") })
+	api.GET("/users", okHandler)
+
+	for host, body := range map[string]string{
+		"api.example.com": "api",
+		"example.com":     "default",
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Host = host
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		assert.Equal(t, body, rec.Body.String())
+		assert.Equal(t, host == "api.example.com", rec.Header().Get("X-Host") == "api")
+	}
+
+	// Routes of a host aren't served for other hosts
+	req := httptest.NewRequest(http.MethodGet, "/users", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestEchoTrailingSlash(t *testing.T) {
+	e := New()
+	h := func(c Context) error { return c.String(http.StatusOK, c.Path()) }
+	e.GET("/strict", h)
+	e.TrailingSlash = TrailingSlashMatch
+	e.GET("/match", h)
+	e.GET("/users/:id<int>/", h)
+	e.GET("/explicit", h)
+	e.GET("/explicit/", func(c Context) error { return c.String(http.StatusOK, "explicit") })
+	g := e.Group("/g")
+	g.TrailingSlash = TrailingSlashRedirect
+	g.GET("/redirect", h)
+	g.POST("/redirect/", h)
+
+	c, _ := request(http.MethodGet, "/strict/", e)
+	assert.Equal(t, http.StatusNotFound, c)
+	c, b := request(http.MethodGet, "/match/", e)
+	assert.Equal(t, http.StatusOK, c)
+	assert.Equal(t, "/match", b)
+	c, _ = request(http.MethodGet, "/users/1", e)
+	assert.Equal(t, http.StatusOK, c)
+	c, _ = request(http.MethodGet, "/users/a", e)
+	assert.Equal(t, http.StatusNotFound, c)
+	_, b = request(http.MethodGet, "/explicit/", e)
+	assert.Equal(t, "explicit", b)
+
+	req := httptest.NewRequest(http.MethodGet, "/g/redirect/?a=1", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusMovedPermanently, rec.Code)
+	assert.Equal(t, "/g/redirect?a=1", rec.Header().Get(HeaderLocation))
+
+	req = httptest.NewRequest(http.MethodPost, "/g/redirect", nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusPermanentRedirect, rec.Code)
+	assert.Equal(t, "/g/redirect/", rec.Header().Get(HeaderLocation))
+}
+
 func TestEchoGroup(t *testing.T) {
 	e := New()
 	buf := new(bytes.Buffer)
@@ -399,6 +568,35 @@ func TestEchoMethodNotAllowed(t *testing.T) {
 	rec := httptest.NewRecorder()
 	e.ServeHTTP(rec, req)
 	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	assert.Equal(t, "GET, OPTIONS", rec.Header().Get(HeaderAllow))
+}
+
+func TestEchoAutoOptions(t *testing.T) {
+	e := New()
+	h := func(c Context) error { return c.NoContent(http.StatusOK) }
+	e.GET("/users", h)
+	e.POST("/users", h)
+	e.PUT("/users/:id", h)
+	e.OPTIONS("/users/:id", h)
+
+	req := httptest.NewRequest(http.MethodOptions, "/users", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, "GET, POST, OPTIONS", rec.Header().Get(HeaderAllow))
+
+	req = httptest.NewRequest(http.MethodDelete, "/users/1", nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	assert.Equal(t, "OPTIONS, PUT", rec.Header().Get(HeaderAllow))
+
+	e.DisableAutoOptions = true
+	req = httptest.NewRequest(http.MethodOptions, "/users", nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	assert.Equal(t, "GET, POST", rec.Header().Get(HeaderAllow))
 }
 
 func TestEchoContext(t *testing.T) {
@@ -416,6 +614,49 @@ func TestEchoStart(t *testing.T) {
 	time.Sleep(200 * time.Millisecond)
 }
 
+func TestEchoStartServers(t *testing.T) {
+	e := New()
+	e.HideBanner = true
+	e.HidePort = true
+	e.GET("/", func(c Context) error {
+		return c.String(http.StatusOK, "OK")
+	})
+	errCh := make(chan error)
+	go func() {
+		errCh <- e.StartServers("127.0.0.1:0", "127.0.0.1:0")
+	}()
+	var listeners []net.Listener
+	for i := 0; i < 100 && len(listeners) == 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+		listeners = e.Listeners()
+	}
+
+	if assert.Len(t, listeners, 2) {
+		for _, l := range listeners {
+			res, err := http.Get("http://" + l.Addr().String())
+			if assert.NoError(t, err) {
+				b, _ := ioutil.ReadAll(res.Body)
+				res.Body.Close()
+				assert.Equal(t, "OK", string(b))
+			}
+		}
+	}
+
+	assert.NoError(t, e.Shutdown(stdContext.Background()))
+	assert.Equal(t, http.ErrServerClosed, <-errCh)
+	for _, l := range listeners {
+		_, err := http.Get("http://" + l.Addr().String())
+		assert.Error(t, err)
+	}
+
+	// Startup errors
+	err := New().StartServers("127.0.0.1:0", "127.0.0.1:99999", "127.0.0.1:-1")
+	if assert.Error(t, err) {
+		assert.Len(t, strings.Split(err.Error(), "; "), 2)
+	}
+	assert.Error(t, New().StartServers())
+}
+
 func TestEchoStartTLS(t *testing.T) {
 	e := New()
 	go func() {
@@ -537,6 +778,69 @@ func TestHTTPError(t *testing.T) {
 	assert.Equal(t, "code=400, message=map[code:12], internal=<nil>", err.Error())
 }
 
+func TestHTTPErrorWithInternal(t *testing.T) {
+	errDB := errors.New("db down")
+	err := ErrServiceUnavailable.WithInternal(errDB)
+	assert.Equal(t, http.StatusServiceUnavailable, err.Code)
+	assert.Equal(t, errDB, err.Internal)
+	assert.Nil(t, ErrServiceUnavailable.Internal)
+	assert.True(t, errors.Is(fmt.Errorf("query: %w", err), errDB))
+
+	var he *HTTPError
+	assert.True(t, errors.As(fmt.Errorf("query: %w", err), &he))
+	assert.Equal(t, err, he)
+}
+
+func TestDefaultHTTPErrorHandlerHTML(t *testing.T) {
+	e := New()
+	e.GET("/", func(c Context) error {
+		return NewHTTPError(http.StatusBadRequest, "invalid <id>")
+	})
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(HeaderAccept, "text/html,application/xhtml+xml")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, MIMETextHTMLCharsetUTF8, rec.Header().Get(HeaderContentType))
+	assert.Contains(t, rec.Body.String(), "<h1>400 Bad Request</h1><pre>invalid &lt;id&gt;</pre>")
+
+	// JSON is preferred
+	req.Header.Set(HeaderAccept, "text/html, application/json")
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, MIMEApplicationJSONCharsetUTF8, rec.Header().Get(HeaderContentType))
+}
+
+func TestEchoRegisterErrorMapping(t *testing.T) {
+	e := New()
+	errNotFound := errors.New("user not found")
+	e.RegisterErrorMapping(errNotFound, http.StatusNotFound)
+
+	// Mapped sentinel error
+	e.GET("/mapped", func(c Context) error {
+		return errNotFound
+	})
+	c, b := request(http.MethodGet, "/mapped", e)
+	assert.Equal(t, http.StatusNotFound, c)
+	assert.Equal(t, `{"message":"user not found"}`+"\n", b)
+
+	// Wrapped sentinel error
+	e.GET("/wrapped", func(c Context) error {
+		return fmt.Errorf("lookup: %w", errNotFound)
+	})
+	c, b = request(http.MethodGet, "/wrapped", e)
+	assert.Equal(t, http.StatusNotFound, c)
+	assert.Equal(t, `{"message":"user not found"}`+"\n", b)
+
+	// Unmapped error
+	e.GET("/unmapped", func(c Context) error {
+		return errors.New("boom")
+	})
+	c, b = request(http.MethodGet, "/unmapped", e)
+	assert.Equal(t, http.StatusInternalServerError, c)
+	assert.Equal(t, `{"message":"Internal Server Error"}`+"\n", b)
+}
+
 func TestEchoClose(t *testing.T) {
 	e := New()
 	errCh := make(chan error)
@@ -557,6 +861,110 @@ func TestEchoClose(t *testing.T) {
 	assert.Equal(t, err.Error(), "http: Server closed")
 }
 
+func TestEchoShutdownOnSignal(t *testing.T) {
+	e := New()
+	started := make(chan struct{})
+	finished := make(chan struct{})
+	e.GET("/", func(c Context) error {
+		close(started)
+		<-finished
+		return c.String(http.StatusOK, "OK")
+	})
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if !assert.NoError(t, err) {
+		return
+	}
+	e.Listener = ln
+	errCh := make(chan error)
+	go func() {
+		errCh <- e.Start("")
+	}()
+	done := e.ShutdownOnSignal(5*time.Second, os.Interrupt)
+
+	resCh := make(chan *http.Response)
+	go func() {
+		res, _ := http.Get("http://" + ln.Addr().String())
+		resCh <- res
+	}()
+	<-started
+	p, _ := os.FindProcess(os.Getpid())
+	assert.NoError(t, p.Signal(os.Interrupt))
+	assert.Equal(t, http.ErrServerClosed, <-errCh)
+
+	// The in-flight request is finished before the shutdown completes
+	close(finished)
+	assert.NoError(t, <-done)
+	if res := <-resCh; assert.NotNil(t, res) {
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		res.Body.Close()
+	}
+}
+
+func TestEchoStartUnixSocket(t *testing.T) {
+	dir, err := ioutil.TempDir("", "echo")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+	sock := filepath.Join(dir, "echo.sock")
+
+	e := New()
+	e.HideBanner = true
+	e.GET("/", func(c Context) error {
+		return c.String(http.StatusOK, "OK")
+	})
+	errCh := make(chan error)
+	go func() {
+		errCh <- e.Start("unix:" + sock)
+	}()
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx stdContext.Context, _, _ string) (net.Conn, error) {
+			return new(net.Dialer).DialContext(ctx, "unix", sock)
+		},
+	}}
+	var res *http.Response
+	for i := 0; i < 50; i++ {
+		if res, err = client.Get("http://echo/"); err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		res.Body.Close()
+	}
+
+	assert.NoError(t, e.Close())
+	assert.Equal(t, http.ErrServerClosed, <-errCh)
+}
+
+func TestSystemdListeners(t *testing.T) {
+	ls, err := SystemdListeners()
+	assert.NoError(t, err)
+	assert.Empty(t, ls)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer ln.Close()
+	f, err := ln.(*net.TCPListener).File()
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer f.Close()
+
+	os.Setenv("LISTEN_PID", strconv.Itoa(os.Getpid()))
+	os.Setenv("LISTEN_FDS", "1")
+	ls, err = systemdListeners(int(f.Fd()))
+	if assert.NoError(t, err) && assert.Len(t, ls, 1) {
+		assert.Equal(t, ln.Addr().String(), ls[0].Addr().String())
+		ls[0].Close()
+	}
+	assert.Empty(t, os.Getenv("LISTEN_FDS"))
+}
+
 func TestEchoShutdown(t *testing.T) {
 	e := New()
 	errCh := make(chan error)
@@ -0,0 +1,106 @@
+// +build go1.16
+
+package echo
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+)
+
+type (
+	// TemplateRenderer is a `Renderer` executing the html/template templates
+	// with the given name.
+	TemplateRenderer struct {
+		Templates *template.Template
+	}
+)
+
+// NewTemplateRendererFS returns a TemplateRenderer with the templates of fsys,
+// e.g. an `embed.FS`, matching patterns.
+func NewTemplateRendererFS(fsys fs.FS, patterns ...string) (*TemplateRenderer, error) {
+	t, err := template.ParseFS(fsys, patterns...)
+	if err != nil {
+		return nil, err
+	}
+	return &TemplateRenderer{Templates: t}, nil
+}
+
+// Render implements `Renderer#Render()`.
+func (r *TemplateRenderer) Render(w io.Writer, name string, data interface{}, c Context) error {
+	return r.Templates.ExecuteTemplate(w, name, data)
+}
+
+// StaticFS registers a new route with path prefix to serve static files from
+// fsys, e.g. an `embed.FS` wrapped with `fs.Sub()`.
+func (e *Echo) StaticFS(prefix string, fsys fs.FS) *Route {
+	return e.staticFS(prefix, fsys, e.GET)
+}
+
+// StaticFS implements `Echo#StaticFS()` for sub-routes within the Group.
+func (g *Group) StaticFS(prefix string, fsys fs.FS) {
+	g.staticFS(prefix, fsys, g.GET)
+}
+
+func (common) staticFS(prefix string, fsys fs.FS, get func(string, HandlerFunc, ...MiddlewareFunc) *Route) *Route {
+	h := func(c Context) error {
+		p, err := url.PathUnescape(c.Param("*"))
+		if err != nil {
+			return err
+		}
+		name := path.Clean("/" + p)[1:] // "/"+ for security
+		if name == "" {
+			name = "."
+		}
+		return fsFile(c, fsys, name)
+	}
+	if prefix == "/" {
+		return get(prefix+"*", h)
+	}
+	return get(prefix+"/*", h)
+}
+
+// fsFile serves the file name of fsys like `Context#File()`.
+func fsFile(c Context, fsys fs.FS, name string) error {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return NotFoundHandler(c)
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if fi.IsDir() {
+		f, err = fsys.Open(path.Join(name, indexPage))
+		if err != nil {
+			return NotFoundHandler(c)
+		}
+		defer f.Close()
+		if fi, err = f.Stat(); err != nil {
+			return err
+		}
+	}
+
+	content, ok := f.(io.ReadSeeker)
+	if !ok {
+		b, err := ioutil.ReadAll(f)
+		if err != nil {
+			return err
+		}
+		content = bytes.NewReader(b)
+	}
+	// Files of an `embed.FS` have no modification time to derive it from
+	if h := c.Response().Header(); h.Get(HeaderETag) == "" && !fi.ModTime().IsZero() {
+		h.Set(HeaderETag, fmt.Sprintf(`W/"%x-%x"`, fi.ModTime().UnixNano(), fi.Size()))
+	}
+	http.ServeContent(c.Response(), c.Request(), fi.Name(), fi.ModTime(), content)
+	return nil
+}
@@ -0,0 +1,67 @@
+//go:build go1.16
+// +build go1.16
+
+package echo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEchoStaticFS(t *testing.T) {
+	e := New()
+	fsys := fstest.MapFS{
+		"index.html":       {Data: []byte("index")},
+		"css/main.css":     {Data: []byte("body{}"), ModTime: time.Now()},
+		"docs/index.html":  {Data: []byte("docs")},
+		"docs/empty/.keep": {},
+	}
+	e.StaticFS("/", fsys)
+	g := e.Group("/assets")
+	g.StaticFS("/static", fsys)
+
+	for path, body := range map[string]string{
+		"/":                           "index",
+		"/css/main.css":               "body{}",
+		"/docs/":                      "docs",
+		"/assets/static/css/main.css": "body{}",
+	} {
+		c, b := request(http.MethodGet, path, e)
+		assert.Equal(t, http.StatusOK, c, path)
+		assert.Equal(t, body, b, path)
+	}
+	for _, path := range []string{"/missing", "/docs/empty", "/../fs.go"} {
+		c, _ := request(http.MethodGet, path, e)
+		assert.Equal(t, http.StatusNotFound, c, path)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/css/main.css", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, "text/css; charset=utf-8", rec.Header().Get(HeaderContentType))
+	assert.NotEmpty(t, rec.Header().Get(HeaderETag))
+}
+
+func TestTemplateRendererFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"templates/a.html": {Data: []byte(`{{define "a"}}Hello, {{.}}!{{end}}`)},
+	}
+	r, err := NewTemplateRendererFS(fsys, "templates/*.html")
+	if !assert.NoError(t, err) {
+		return
+	}
+	e := New()
+	e.Renderer = r
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+	assert.NoError(t, c.Render(http.StatusOK, "a", "<Jon>"))
+	assert.Equal(t, "Hello, &lt;Jon&gt;!", rec.Body.String())
+
+	_, err = NewTemplateRendererFS(fsys, "missing/*.html")
+	assert.Error(t, err)
+}
@@ -14,6 +14,17 @@ type (
 		prefix     string
 		middleware []MiddlewareFunc
 		echo       *Echo
+		parent     *Group
+		// HTTPErrorHandler handles the errors of the group's routes and
+		// middleware instead of `Echo#HTTPErrorHandler`, e.g. to render HTML
+		// error pages for a group serving pages. Sub-groups inherit it.
+		HTTPErrorHandler HTTPErrorHandler
+		// Renderer is used by `Context#Render()` for the group's routes instead
+		// of `Echo#Renderer`. Sub-groups inherit it.
+		Renderer Renderer
+		// TrailingSlash overrides `Echo#TrailingSlash` for routes added to the
+		// group afterwards. Sub-groups inherit it.
+		TrailingSlash TrailingSlashPolicy
 	}
 )
 
@@ -99,6 +110,7 @@ func (g *Group) Group(prefix string, middleware ...MiddlewareFunc) (sg *Group) {
 	m = append(m, middleware...)
 	sg = g.echo.Group(g.prefix+prefix, m...)
 	sg.host = g.host
+	sg.parent = g
 	return
 }
 
@@ -117,8 +129,49 @@ func (g *Group) Add(method, path string, handler HandlerFunc, middleware ...Midd
 	// Combine into a new slice to avoid accidentally passing the same slice for
 	// multiple routes, which would lead to later add() calls overwriting the
 	// middleware from earlier calls.
-	m := make([]MiddlewareFunc, 0, len(g.middleware)+len(middleware))
+	m := make([]MiddlewareFunc, 0, len(g.middleware)+len(middleware)+1)
+	m = append(m, g.scope)
 	m = append(m, g.middleware...)
 	m = append(m, middleware...)
-	return g.echo.add(g.host, method, g.prefix+path, handler, m...)
+	return g.echo.add(g.host, method, g.prefix+path, g.trailingSlash(), handler, m...)
+}
+
+// trailingSlash returns the trailing slash policy of the group, inherited from
+// its parents or the Echo instance if not set.
+func (g *Group) trailingSlash() TrailingSlashPolicy {
+	for sg := g; sg != nil; sg = sg.parent {
+		if sg.TrailingSlash != 0 {
+			return sg.TrailingSlash
+		}
+	}
+	return g.echo.TrailingSlash
+}
+
+// scope is the outermost middleware of the group's routes, applying the
+// group-level error handler and renderer. They are looked up on each request,
+// so they can be set after adding routes.
+func (g *Group) scope(next HandlerFunc) HandlerFunc {
+	return func(c Context) error {
+		var (
+			errorHandler HTTPErrorHandler
+			renderer     Renderer
+		)
+		for sg := g; sg != nil; sg = sg.parent {
+			if errorHandler == nil {
+				errorHandler = sg.HTTPErrorHandler
+			}
+			if renderer == nil {
+				renderer = sg.Renderer
+			}
+		}
+		if renderer != nil {
+			c.matched().renderer = renderer
+		}
+		err := next(c)
+		if err != nil && errorHandler != nil {
+			errorHandler(err, c)
+			return nil
+		}
+		return err
+	}
 }
@@ -1,6 +1,8 @@
 package echo
 
 import (
+	"fmt"
+	"io"
 	"net/http"
 	"testing"
 
@@ -65,3 +67,60 @@ func TestGroupRouteMiddleware(t *testing.T) {
 	c, _ = request(http.MethodGet, "/group/405", e)
 	assert.Equal(t, 405, c)
 }
+
+type nameRenderer string
+
+func (r nameRenderer) Render(w io.Writer, name string, data interface{}, c Context) error {
+	_, err := fmt.Fprintf(w, "%s: %s", r, name)
+	return err
+}
+
+func TestGroupErrorHandlerAndRenderer(t *testing.T) {
+	e := New()
+	e.Renderer = nameRenderer("echo")
+	h := func(c Context) error {
+		return c.Render(http.StatusOK, "page", nil)
+	}
+	fail := func(c Context) error {
+		return ErrForbidden
+	}
+	e.GET("/", h)
+	e.GET("/fail", fail)
+
+	pages := e.Group("/pages", func(next HandlerFunc) HandlerFunc {
+		return next
+	})
+	pages.GET("", h)
+	pages.GET("/fail", fail)
+	// Set after adding routes
+	pages.Renderer = nameRenderer("pages")
+	pages.HTTPErrorHandler = func(err error, c Context) {
+		c.HTML(err.(*HTTPError).Code, "<h1>error</h1>")
+	}
+	admin := pages.Group("/admin")
+	admin.GET("", h)
+	admin.GET("/fail", fail)
+
+	tests := []struct {
+		path string
+		code int
+		body string
+	}{
+		{"/", http.StatusOK, "echo: page"},
+		{"/fail", http.StatusForbidden, `{"message":"Forbidden"}` + "\n"},
+		{"/pages", http.StatusOK, "pages: page"},
+		{"/pages/fail", http.StatusForbidden, "<h1>error</h1>"},
+		{"/pages/unknown", http.StatusNotFound, "<h1>error</h1>"},
+		{"/pages/admin", http.StatusOK, "pages: page"},
+		{"/pages/admin/fail", http.StatusForbidden, "<h1>error</h1>"},
+	}
+	for _, tt := range tests {
+		code, body := request(http.MethodGet, tt.path, e)
+		assert.Equal(t, tt.code, code, tt.path)
+		assert.Equal(t, tt.body, body, tt.path)
+	}
+
+	admin.Renderer = nameRenderer("admin")
+	_, body := request(http.MethodGet, "/pages/admin", e)
+	assert.Equal(t, "admin: page", body)
+}
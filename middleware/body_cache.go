@@ -0,0 +1,113 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/labstack/echo/v4"
+	gbytes "github.com/labstack/gommon/bytes"
+)
+
+type (
+	// BodyCacheConfig defines the config for BodyCache middleware.
+	BodyCacheConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// Maximum allowed size for a cached request body, it can be specified
+		// as `4x` or `4xB`, where x is one of the multiple from K, M, G, T or P.
+		// Optional. Default value "4M".
+		Limit string `yaml:"limit"`
+	}
+
+	cachedBody struct {
+		*bytes.Reader
+	}
+)
+
+var (
+	// DefaultBodyCacheConfig is the default BodyCache middleware config.
+	DefaultBodyCacheConfig = BodyCacheConfig{
+		Skipper: DefaultSkipper,
+		Limit:   "4M",
+	}
+
+	// ErrBodyNotRewindable is returned by `RewindBody()` if the request body
+	// hasn't been cached by the BodyCache middleware.
+	ErrBodyNotRewindable = errors.New("request body is not rewindable")
+)
+
+// BodyCache returns a BodyCache middleware.
+//
+// BodyCache middleware reads the request body once into memory and replaces it
+// with a seekable reader, so the body can be read again, e.g. by the binder
+// after a middleware inspected it or when a handler is retried. Use
+// `RewindBody()` to read the body from the start again. If the body exceeds the
+// limit, it sends "413 - Request Entity Too Large" response.
+func BodyCache() echo.MiddlewareFunc {
+	return BodyCacheWithConfig(DefaultBodyCacheConfig)
+}
+
+// BodyCacheWithConfig returns a BodyCache middleware with config.
+// See: `BodyCache()`.
+func BodyCacheWithConfig(config BodyCacheConfig) echo.MiddlewareFunc {
+	// Defaults
+	if config.Skipper == nil {
+		config.Skipper = DefaultBodyCacheConfig.Skipper
+	}
+	if config.Limit == "" {
+		config.Limit = DefaultBodyCacheConfig.Limit
+	}
+
+	limit, err := gbytes.Parse(config.Limit)
+	if err != nil {
+		panic(fmt.Errorf("echo: invalid body-cache limit=%s", config.Limit))
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if config.Skipper(c) {
+				return next(c)
+			}
+
+			req := c.Request()
+			if req.ContentLength > limit {
+				return echo.ErrStatusRequestEntityTooLarge
+			}
+
+			b, err := ioutil.ReadAll(io.LimitReader(req.Body, limit+1))
+			if err != nil {
+				return err
+			}
+			if int64(len(b)) > limit {
+				return echo.ErrStatusRequestEntityTooLarge
+			}
+			req.Body.Close()
+
+			req.Body = cachedBody{bytes.NewReader(b)}
+			req.ContentLength = int64(len(b))
+			req.GetBody = func() (io.ReadCloser, error) {
+				return cachedBody{bytes.NewReader(b)}, nil
+			}
+			return next(c)
+		}
+	}
+}
+
+// RewindBody seeks the request body cached by the BodyCache middleware back to
+// the start so it can be read again.
+func RewindBody(c echo.Context) error {
+	s, ok := c.Request().Body.(io.Seeker)
+	if !ok {
+		return ErrBodyNotRewindable
+	}
+	_, err := s.Seek(0, io.SeekStart)
+	return err
+}
+
+func (cachedBody) Close() error {
+	return nil
+}
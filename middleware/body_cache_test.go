@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBodyCache(t *testing.T) {
+	e := echo.New()
+	body := `{"id":1,"name":"Jon Snow"}`
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	type user struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+	inspect := func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			b, err := ioutil.ReadAll(c.Request().Body)
+			if err != nil {
+				return err
+			}
+			assert.Equal(t, body, string(b))
+			if err := RewindBody(c); err != nil {
+				return err
+			}
+			return next(c)
+		}
+	}
+	h := func(c echo.Context) error {
+		u := new(user)
+		if err := c.Bind(u); err != nil {
+			return err
+		}
+		return c.String(http.StatusOK, u.Name)
+	}
+
+	assert := assert.New(t)
+
+	if assert.NoError(BodyCache()(inspect(h))(c)) {
+		assert.Equal(http.StatusOK, rec.Code)
+		assert.Equal("Jon Snow", rec.Body.String())
+	}
+
+	// Over limit
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	c = e.NewContext(req, httptest.NewRecorder())
+	mw := BodyCacheWithConfig(BodyCacheConfig{Limit: "10B"})
+	he := mw(h)(c).(*echo.HTTPError)
+	assert.Equal(http.StatusRequestEntityTooLarge, he.Code)
+
+	// Over limit with unknown content length
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.ContentLength = -1
+	c = e.NewContext(req, httptest.NewRecorder())
+	he = mw(h)(c).(*echo.HTTPError)
+	assert.Equal(http.StatusRequestEntityTooLarge, he.Code)
+
+	// Not cached
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	c = e.NewContext(req, httptest.NewRecorder())
+	assert.Equal(ErrBodyNotRewindable, RewindBody(c))
+}
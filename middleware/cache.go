@@ -0,0 +1,269 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	// CacheConfig defines the config for Cache middleware.
+	CacheConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// Store is where cached responses are kept.
+		// Optional. Default value an in-memory store.
+		Store CacheStore
+
+		// TTL is how long a response is cached for.
+		// Optional. Default value 1 minute.
+		TTL time.Duration `yaml:"ttl"`
+
+		// VaryHeaders is a list of request headers whose values are part of the
+		// cache key, e.g. `Accept-Encoding`.
+		// Optional.
+		VaryHeaders []string `yaml:"vary_headers"`
+
+		// CacheAuthorized makes requests with `Authorization` or `Cookie`
+		// header served cached responses, and their responses cacheable without
+		// `Cache-Control: public`. Only set it if responses are the same for
+		// all users, as they are served to any of them.
+		// Optional. Default value false.
+		CacheAuthorized bool `yaml:"cache_authorized"`
+	}
+
+	// CacheStore defines the interface for storing cached responses.
+	CacheStore interface {
+		// Get returns the response cached for key, if it didn't expire.
+		Get(key string) (*CachedResponse, bool)
+
+		// Set caches the response for key for the duration of ttl.
+		Set(key string, res *CachedResponse, ttl time.Duration)
+	}
+
+	// CachedResponse is a response stored by the Cache middleware.
+	CachedResponse struct {
+		Status   int
+		Header   http.Header
+		Body     []byte
+		StoredAt time.Time
+	}
+
+	// CacheMemoryStore is an in-memory `CacheStore`.
+	CacheMemoryStore struct {
+		// MaxEntries is the maximum number of responses kept. Once reached,
+		// expired responses are dropped, and then the ones expiring first.
+		// Optional. Default value `DefaultCacheMaxEntries`.
+		MaxEntries int
+
+		mutex   sync.RWMutex
+		entries map[string]cacheEntry
+	}
+
+	cacheEntry struct {
+		response  *CachedResponse
+		expiresAt time.Time
+	}
+)
+
+const (
+	// DefaultCacheMaxEntries is the default maximum number of responses kept
+	// by a `CacheMemoryStore`.
+	DefaultCacheMaxEntries = 10000
+)
+
+var (
+	// DefaultCacheConfig is the default Cache middleware config.
+	DefaultCacheConfig = CacheConfig{
+		Skipper: DefaultSkipper,
+		TTL:     time.Minute,
+	}
+)
+
+// Cache returns a Cache middleware.
+//
+// Cache middleware caches successful responses to GET and HEAD requests, keyed
+// by method, host, URI and vary headers. On a hit the cached response is written with
+// an `Age` header and the rest of the chain is not executed. Responses with
+// `Cache-Control: no-store` or `private`, or setting cookies, are not cached,
+// nor responses to requests with `Authorization` or `Cookie` header unless
+// they have `Cache-Control: public`. Such requests are never served cached
+// responses.
+func Cache() echo.MiddlewareFunc {
+	return CacheWithConfig(DefaultCacheConfig)
+}
+
+// CacheWithConfig returns a Cache middleware with config.
+// See: `Cache()`.
+func CacheWithConfig(config CacheConfig) echo.MiddlewareFunc {
+	// Defaults
+	if config.Skipper == nil {
+		config.Skipper = DefaultCacheConfig.Skipper
+	}
+	if config.Store == nil {
+		config.Store = NewCacheMemoryStore()
+	}
+	if config.TTL == 0 {
+		config.TTL = DefaultCacheConfig.TTL
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) (err error) {
+			if config.Skipper(c) {
+				return next(c)
+			}
+
+			req := c.Request()
+			if req.Method != http.MethodGet && req.Method != http.MethodHead {
+				return next(c)
+			}
+
+			// Responses to authorized requests may be personal, so cached ones
+			// are only served to anonymous requests.
+			authorized := !config.CacheAuthorized &&
+				(req.Header.Get(echo.HeaderAuthorization) != "" || req.Header.Get(echo.HeaderCookie) != "")
+			key := cacheKey(req, config.VaryHeaders)
+			if cached, ok := config.Store.Get(key); ok && !authorized {
+				res := c.Response()
+				for k, v := range cached.Header {
+					res.Header()[k] = append([]string(nil), v...)
+				}
+				age := int64(time.Since(cached.StoredAt) / time.Second)
+				res.Header().Set(echo.HeaderAge, strconv.FormatInt(age, 10))
+				res.WriteHeader(cached.Status)
+				if req.Method != http.MethodHead {
+					_, err = res.Write(cached.Body)
+				}
+				return
+			}
+
+			// Capture the response
+			res := c.Response()
+			body := new(bytes.Buffer)
+			writer := &bodyDumpResponseWriter{
+				Writer:         io.MultiWriter(res.Writer, body),
+				ResponseWriter: res.Writer,
+			}
+			res.Writer = writer
+			defer func() {
+				res.Writer = writer.ResponseWriter
+			}()
+
+			if err = next(c); err != nil {
+				return
+			}
+			if res.Status != http.StatusOK || !cacheable(res.Header()) {
+				return
+			}
+			if authorized && !public(res.Header()) {
+				return
+			}
+			config.Store.Set(key, &CachedResponse{
+				Status:   res.Status,
+				Header:   cloneHeader(res.Header()),
+				Body:     body.Bytes(),
+				StoredAt: time.Now(),
+			}, config.TTL)
+			return
+		}
+	}
+}
+
+func cacheKey(req *http.Request, varyHeaders []string) string {
+	key := req.Method + " " + req.Host + req.URL.RequestURI()
+	for _, h := range varyHeaders {
+		key += "\n" + h + ": " + strings.Join(req.Header[http.CanonicalHeaderKey(h)], ",")
+	}
+	return key
+}
+
+func cloneHeader(header http.Header) http.Header {
+	h := make(http.Header, len(header))
+	for k, v := range header {
+		h[k] = append([]string(nil), v...)
+	}
+	return h
+}
+
+func cacheable(header http.Header) bool {
+	if header.Get(echo.HeaderSetCookie) != "" {
+		return false
+	}
+	for _, directive := range strings.Split(header.Get(echo.HeaderCacheControl), ",") {
+		switch strings.ToLower(strings.TrimSpace(directive)) {
+		case "no-store", "no-cache", "private":
+			return false
+		}
+	}
+	return true
+}
+
+// public reports whether the response has `Cache-Control: public`, allowing to
+// cache it even if the request was authorized.
+func public(header http.Header) bool {
+	for _, directive := range strings.Split(header.Get(echo.HeaderCacheControl), ",") {
+		if strings.EqualFold(strings.TrimSpace(directive), "public") {
+			return true
+		}
+	}
+	return false
+}
+
+// NewCacheMemoryStore returns a new in-memory `CacheStore`.
+func NewCacheMemoryStore() *CacheMemoryStore {
+	return &CacheMemoryStore{
+		MaxEntries: DefaultCacheMaxEntries,
+		entries:    map[string]cacheEntry{},
+	}
+}
+
+// Get implements `CacheStore#Get()`.
+func (s *CacheMemoryStore) Get(key string) (*CachedResponse, bool) {
+	s.mutex.RLock()
+	e, ok := s.entries[key]
+	s.mutex.RUnlock()
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(e.expiresAt) {
+		s.mutex.Lock()
+		delete(s.entries, key)
+		s.mutex.Unlock()
+		return nil, false
+	}
+	return e.response, true
+}
+
+// Set implements `CacheStore#Set()`.
+func (s *CacheMemoryStore) Set(key string, res *CachedResponse, ttl time.Duration) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	now := time.Now()
+	if _, ok := s.entries[key]; !ok && s.MaxEntries > 0 && len(s.entries) >= s.MaxEntries {
+		s.evict(now)
+	}
+	s.entries[key] = cacheEntry{response: res, expiresAt: now.Add(ttl)}
+}
+
+// evict drops the expired entries, or the one expiring first if none did.
+func (s *CacheMemoryStore) evict(now time.Time) {
+	first := ""
+	var firstExpiresAt time.Time
+	for k, e := range s.entries {
+		if now.After(e.expiresAt) {
+			delete(s.entries, k)
+		} else if first == "" || e.expiresAt.Before(firstExpiresAt) {
+			first, firstExpiresAt = k, e.expiresAt
+		}
+	}
+	if len(s.entries) >= s.MaxEntries {
+		delete(s.entries, first)
+	}
+}
@@ -0,0 +1,154 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache(t *testing.T) {
+	e := echo.New()
+	calls := 0
+	e.Use(CacheWithConfig(CacheConfig{
+		VaryHeaders: []string{echo.HeaderAcceptEncoding},
+	}))
+	e.GET("/", func(c echo.Context) error {
+		calls++
+		c.Response().Header().Set("X-Calls", "1")
+		return c.String(http.StatusOK, "test")
+	})
+	e.GET("/no-store", func(c echo.Context) error {
+		calls++
+		c.Response().Header().Set(echo.HeaderCacheControl, "no-store")
+		return c.String(http.StatusOK, "test")
+	})
+	e.GET("/error", func(c echo.Context) error {
+		calls++
+		return echo.ErrBadRequest
+	})
+	request := func(method, path, encoding string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set(echo.HeaderAcceptEncoding, encoding)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	assert := assert.New(t)
+
+	// Miss
+	rec := request(http.MethodGet, "/", "")
+	assert.Equal(http.StatusOK, rec.Code)
+	assert.Equal("test", rec.Body.String())
+	assert.Empty(rec.Header().Get(echo.HeaderAge))
+	assert.Equal(1, calls)
+
+	// Hit
+	rec = request(http.MethodGet, "/", "")
+	assert.Equal(http.StatusOK, rec.Code)
+	assert.Equal("test", rec.Body.String())
+	assert.Equal("1", rec.Header().Get("X-Calls"))
+	assert.Equal(echo.MIMETextPlainCharsetUTF8, rec.Header().Get(echo.HeaderContentType))
+	assert.Equal("0", rec.Header().Get(echo.HeaderAge))
+	assert.Equal(1, calls)
+
+	// Different query and vary header
+	request(http.MethodGet, "/?page=2", "")
+	assert.Equal(2, calls)
+	request(http.MethodGet, "/", "gzip")
+	assert.Equal(3, calls)
+
+	// Different host
+	req := httptest.NewRequest(http.MethodGet, "http://other.com/", nil)
+	e.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Equal(4, calls)
+
+	// Not cacheable
+	request(http.MethodGet, "/no-store", "")
+	rec = request(http.MethodGet, "/no-store", "")
+	assert.Equal("test", rec.Body.String())
+	assert.Equal(6, calls)
+
+	request(http.MethodGet, "/error", "")
+	rec = request(http.MethodGet, "/error", "")
+	assert.Equal(http.StatusBadRequest, rec.Code)
+	assert.Equal(8, calls)
+}
+
+func TestCacheMemoryStore(t *testing.T) {
+	s := NewCacheMemoryStore()
+	res := &CachedResponse{Status: http.StatusOK, Body: []byte("test")}
+
+	s.Set("a", res, time.Minute)
+	cached, ok := s.Get("a")
+	if assert.True(t, ok) {
+		assert.Equal(t, res, cached)
+	}
+
+	s.Set("b", res, -time.Second)
+	_, ok = s.Get("b")
+	assert.False(t, ok)
+
+	_, ok = s.Get("c")
+	assert.False(t, ok)
+}
+
+func TestCacheMemoryStoreMaxEntries(t *testing.T) {
+	s := NewCacheMemoryStore()
+	s.MaxEntries = 2
+	res := &CachedResponse{Status: http.StatusOK}
+
+	s.Set("a", res, time.Minute)
+	s.Set("b", res, 2*time.Minute)
+	s.Set("c", res, 3*time.Minute)
+	assert.Len(t, s.entries, 2)
+	_, ok := s.Get("a")
+	assert.False(t, ok)
+	_, ok = s.Get("c")
+	assert.True(t, ok)
+
+	// Expired entries are dropped first
+	s.Set("b", res, -time.Second)
+	s.Set("d", res, time.Minute)
+	_, ok = s.Get("c")
+	assert.True(t, ok)
+	_, ok = s.Get("d")
+	assert.True(t, ok)
+}
+
+func TestCacheAuthorized(t *testing.T) {
+	e := echo.New()
+	e.Use(Cache())
+	e.GET("/me", func(c echo.Context) error {
+		return c.String(http.StatusOK, c.Request().Header.Get(echo.HeaderAuthorization))
+	})
+	e.GET("/public", func(c echo.Context) error {
+		c.Response().Header().Set(echo.HeaderCacheControl, "public")
+		return c.String(http.StatusOK, c.Request().Header.Get(echo.HeaderAuthorization))
+	})
+	request := func(path, auth string) string {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if auth != "" {
+			req.Header.Set(echo.HeaderAuthorization, auth)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec.Body.String()
+	}
+
+	assert := assert.New(t)
+
+	assert.Equal("Bearer a", request("/me", "Bearer a"))
+	assert.Equal("Bearer b", request("/me", "Bearer b"))
+	assert.Equal("", request("/me", ""))
+	assert.Equal("Bearer a", request("/me", "Bearer a"))
+
+	// Public responses are cached, for anonymous requests
+	assert.Equal("Bearer a", request("/public", "Bearer a"))
+	assert.Equal("Bearer b", request("/public", "Bearer b"))
+	assert.Equal("Bearer b", request("/public", ""))
+}
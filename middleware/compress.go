@@ -3,11 +3,15 @@ package middleware
 import (
 	"bufio"
 	"compress/gzip"
+	"compress/zlib"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"net"
 	"net/http"
+	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/labstack/echo/v4"
 )
@@ -27,6 +31,46 @@ type (
 		io.Writer
 		http.ResponseWriter
 	}
+
+	// CompressConfig defines the config for Compress middleware.
+	CompressConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// Compression level of gzip and deflate.
+		// Optional. Default value -1.
+		Level int `yaml:"level"`
+
+		// MinLength is the minimum length of responses to be compressed.
+		// Shorter responses are sent uncompressed.
+		// Optional. Default value 0.
+		MinLength int `yaml:"min_length"`
+
+		// ContentTypes is a list of media types, or prefixes of them like
+		// "text/", of responses to be compressed.
+		// Optional. Default value compresses responses of any type.
+		ContentTypes []string `yaml:"content_types"`
+	}
+
+	compressWriter interface {
+		io.WriteCloser
+		Flush() error
+		Reset(io.Writer)
+	}
+
+	// compressResponseWriter buffers the start of the response until it's
+	// known whether to compress it.
+	compressResponseWriter struct {
+		http.ResponseWriter
+		config   *CompressConfig
+		scheme   string
+		pool     *sync.Pool
+		writer   compressWriter
+		code     int
+		buf      []byte
+		decided  bool
+		compress bool
+	}
 )
 
 const (
@@ -39,6 +83,12 @@ var (
 		Skipper: DefaultSkipper,
 		Level:   -1,
 	}
+
+	// DefaultCompressConfig is the default Compress middleware config.
+	DefaultCompressConfig = CompressConfig{
+		Skipper: DefaultSkipper,
+		Level:   -1,
+	}
 )
 
 // Gzip returns a middleware which compresses HTTP response using gzip compression
@@ -119,3 +169,199 @@ func (w *gzipResponseWriter) Flush() {
 func (w *gzipResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
 	return w.ResponseWriter.(http.Hijacker).Hijack()
 }
+
+// Compress returns a middleware which compresses HTTP responses with gzip or
+// deflate, whichever the client prefers according to its Accept-Encoding
+// header.
+func Compress() echo.MiddlewareFunc {
+	return CompressWithConfig(DefaultCompressConfig)
+}
+
+// CompressWithConfig returns a Compress middleware with config.
+// See: `Compress()`.
+func CompressWithConfig(config CompressConfig) echo.MiddlewareFunc {
+	// Defaults
+	if config.Skipper == nil {
+		config.Skipper = DefaultCompressConfig.Skipper
+	}
+	if config.Level == 0 {
+		config.Level = DefaultCompressConfig.Level
+	}
+	if _, err := gzip.NewWriterLevel(ioutil.Discard, config.Level); err != nil {
+		panic(fmt.Sprintf("echo: invalid compression level=%d", config.Level))
+	}
+
+	pools := map[string]*sync.Pool{
+		gzipScheme: {New: func() interface{} {
+			w, _ := gzip.NewWriterLevel(ioutil.Discard, config.Level)
+			return w
+		}},
+		deflateScheme: {New: func() interface{} {
+			w, _ := zlib.NewWriterLevel(ioutil.Discard, config.Level)
+			return w
+		}},
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if config.Skipper(c) {
+				return next(c)
+			}
+
+			res := c.Response()
+			res.Header().Add(echo.HeaderVary, echo.HeaderAcceptEncoding)
+			scheme := compressScheme(c.Request().Header.Get(echo.HeaderAcceptEncoding))
+			if scheme == "" || c.Request().Method == http.MethodHead {
+				return next(c)
+			}
+
+			rw := res.Writer
+			cw := &compressResponseWriter{
+				ResponseWriter: rw,
+				config:         &config,
+				scheme:         scheme,
+				pool:           pools[scheme],
+			}
+			res.Writer = cw
+			defer func() {
+				if !res.Committed {
+					// Lets the error handler send the response uncompressed
+					res.Writer = rw
+					return
+				}
+				cw.close()
+			}()
+			return next(c)
+		}
+	}
+}
+
+// compressScheme returns the scheme with the highest quality in the
+// Accept-Encoding header, preferring gzip, or an empty string if neither gzip
+// nor deflate is accepted.
+func compressScheme(acceptEncoding string) string {
+	qualities := map[string]float64{}
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, q := part, 1.0
+		if i := strings.IndexByte(part, ';'); i != -1 {
+			name = part[:i]
+			param := strings.TrimSpace(part[i+1:])
+			if strings.HasPrefix(param, "q=") {
+				if v, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					q = v
+				}
+			}
+		}
+		qualities[strings.ToLower(strings.TrimSpace(name))] = q
+	}
+	quality := func(scheme string) float64 {
+		if q, ok := qualities[scheme]; ok {
+			return q
+		}
+		return qualities["*"]
+	}
+	gq, dq := quality(gzipScheme), quality(deflateScheme)
+	switch {
+	case gq > 0 && gq >= dq:
+		return gzipScheme
+	case dq > 0:
+		return deflateScheme
+	}
+	return ""
+}
+
+func (w *compressResponseWriter) WriteHeader(code int) {
+	w.code = code
+	if code < http.StatusOK || code == http.StatusNoContent || code == http.StatusNotModified {
+		w.decide(false)
+	}
+}
+
+func (w *compressResponseWriter) Write(b []byte) (int, error) {
+	if !w.decided {
+		w.buf = append(w.buf, b...)
+		if len(w.buf) >= w.config.MinLength {
+			if err := w.decide(true); err != nil {
+				return 0, err
+			}
+		}
+		return len(b), nil
+	}
+	if w.compress {
+		return w.writer.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// decide sends the header, compressing the response if try is set and it's
+// of a type to be compressed, followed by the buffered start of the response.
+func (w *compressResponseWriter) decide(try bool) (err error) {
+	if w.decided {
+		return
+	}
+	w.decided = true
+	if w.code == 0 {
+		w.code = http.StatusOK
+	}
+	h := w.Header()
+	if len(w.buf) > 0 && h.Get(echo.HeaderContentType) == "" {
+		h.Set(echo.HeaderContentType, http.DetectContentType(w.buf))
+	}
+	w.compress = try && len(w.buf) > 0 && w.code != http.StatusPartialContent &&
+		h.Get(echo.HeaderContentEncoding) == "" && w.compressible(h.Get(echo.HeaderContentType))
+	if w.compress {
+		h.Set(echo.HeaderContentEncoding, w.scheme)
+		h.Del(echo.HeaderContentLength)
+		w.writer = w.pool.Get().(compressWriter)
+		w.writer.Reset(w.ResponseWriter)
+	}
+	w.ResponseWriter.WriteHeader(w.code)
+	if len(w.buf) > 0 {
+		buf := w.buf
+		w.buf = nil
+		_, err = w.Write(buf)
+	}
+	return
+}
+
+func (w *compressResponseWriter) compressible(contentType string) bool {
+	if len(w.config.ContentTypes) == 0 {
+		return true
+	}
+	if i := strings.IndexByte(contentType, ';'); i != -1 {
+		contentType = contentType[:i]
+	}
+	contentType = strings.ToLower(strings.TrimSpace(contentType))
+	for _, t := range w.config.ContentTypes {
+		if strings.HasPrefix(contentType, strings.ToLower(t)) {
+			return true
+		}
+	}
+	return false
+}
+
+// close sends the buffered response if it's shorter than the minimum length
+// and finishes the compressed stream.
+func (w *compressResponseWriter) close() {
+	w.decide(false)
+	if w.compress {
+		w.writer.Close()
+		w.writer.Reset(ioutil.Discard)
+		w.pool.Put(w.writer)
+	}
+}
+
+func (w *compressResponseWriter) Flush() {
+	// More might be written after flushing, so the minimum length is ignored
+	w.decide(true)
+	if w.compress {
+		w.writer.Flush()
+	}
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (w *compressResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return w.ResponseWriter.(http.Hijacker).Hijack()
+}
@@ -3,10 +3,12 @@ package middleware
 import (
 	"bytes"
 	"compress/gzip"
+	"compress/zlib"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/labstack/echo/v4"
@@ -146,3 +148,93 @@ func TestGzipWithStatic(t *testing.T) {
 		}
 	}
 }
+
+func TestCompress(t *testing.T) {
+	e := echo.New()
+	e.Use(CompressWithConfig(CompressConfig{
+		MinLength:    10,
+		ContentTypes: []string{"text/", echo.MIMEApplicationJSON},
+	}))
+	body := strings.Repeat("compress ", 10)
+	e.GET("/text", func(c echo.Context) error {
+		return c.String(http.StatusOK, body)
+	})
+	e.GET("/short", func(c echo.Context) error {
+		return c.String(http.StatusOK, "short")
+	})
+	e.GET("/image", func(c echo.Context) error {
+		return c.Blob(http.StatusOK, "image/png", []byte(body))
+	})
+	e.GET("/error", func(c echo.Context) error {
+		return echo.ErrForbidden
+	})
+	request := func(path, acceptEncoding string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set(echo.HeaderAcceptEncoding, acceptEncoding)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Gzip
+	rec := request("/text", "deflate;q=0.5, gzip")
+	assert.Equal(t, gzipScheme, rec.Header().Get(echo.HeaderContentEncoding))
+	assert.Equal(t, echo.HeaderAcceptEncoding, rec.Header().Get(echo.HeaderVary))
+	r, err := gzip.NewReader(rec.Body)
+	if assert.NoError(t, err) {
+		b, _ := ioutil.ReadAll(r)
+		assert.Equal(t, body, string(b))
+	}
+
+	// Deflate
+	rec = request("/text", "gzip;q=0.5, deflate")
+	assert.Equal(t, deflateScheme, rec.Header().Get(echo.HeaderContentEncoding))
+	zr, err := zlib.NewReader(rec.Body)
+	assert.NoError(t, err)
+	b, _ := ioutil.ReadAll(zr)
+	assert.Equal(t, body, string(b))
+
+	rec = request("/text", "gzip;q=0, *")
+	assert.Equal(t, deflateScheme, rec.Header().Get(echo.HeaderContentEncoding))
+
+	// Not accepted
+	for _, ae := range []string{"", "br", "gzip;q=0"} {
+		rec = request("/text", ae)
+		assert.Empty(t, rec.Header().Get(echo.HeaderContentEncoding), ae)
+		assert.Equal(t, body, rec.Body.String())
+	}
+
+	// Too short, not an allowed type or an error
+	for _, path := range []string{"/short", "/image", "/error"} {
+		rec = request(path, gzipScheme)
+		assert.Empty(t, rec.Header().Get(echo.HeaderContentEncoding), path)
+	}
+	assert.Equal(t, "short", request("/short", gzipScheme).Body.String())
+	assert.Equal(t, http.StatusForbidden, request("/error", gzipScheme).Code)
+}
+
+func TestCompressFlush(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(echo.HeaderAcceptEncoding, gzipScheme)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	h := CompressWithConfig(CompressConfig{MinLength: 1024})(func(c echo.Context) error {
+		c.Response().Write([]byte("first"))
+		c.Response().Flush()
+		assert.True(t, rec.Flushed)
+		assert.Equal(t, gzipScheme, rec.Header().Get(echo.HeaderContentEncoding))
+		c.Response().Write([]byte(" second"))
+		return nil
+	})
+	assert.NoError(t, h(c))
+	r, err := gzip.NewReader(rec.Body)
+	if assert.NoError(t, err) {
+		b, _ := ioutil.ReadAll(r)
+		assert.Equal(t, "first second", string(b))
+	}
+
+	assert.Panics(t, func() {
+		CompressWithConfig(CompressConfig{Level: 10})
+	})
+}
@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	// ContentTypeConfig defines the config for ContentType middleware.
+	ContentTypeConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// AllowContentTypes is a list of media types accepted in the request
+		// `Content-Type` header. Parameters such as charset are ignored and a
+		// subtype of `*` matches any subtype, e.g. `application/*`.
+		// Required.
+		AllowContentTypes []string `yaml:"allow_content_types"`
+	}
+)
+
+var (
+	// DefaultContentTypeConfig is the default ContentType middleware config.
+	DefaultContentTypeConfig = ContentTypeConfig{
+		Skipper: DefaultSkipper,
+	}
+)
+
+// ContentType returns a ContentType middleware.
+//
+// ContentType middleware checks the request `Content-Type` header against the
+// allowed media types before calling the next handler. If the content type is
+// not allowed, or is missing for a POST, PUT or PATCH request with a body, it
+// sends "415 - Unsupported Media Type" response.
+func ContentType(types ...string) echo.MiddlewareFunc {
+	c := DefaultContentTypeConfig
+	c.AllowContentTypes = types
+	return ContentTypeWithConfig(c)
+}
+
+// ContentTypeWithConfig returns a ContentType middleware with config.
+// See: `ContentType()`.
+func ContentTypeWithConfig(config ContentTypeConfig) echo.MiddlewareFunc {
+	// Defaults
+	if config.Skipper == nil {
+		config.Skipper = DefaultContentTypeConfig.Skipper
+	}
+	if len(config.AllowContentTypes) == 0 {
+		panic("echo: content-type middleware requires at least one allowed content type")
+	}
+
+	// Initialize
+	allowed := make([]string, len(config.AllowContentTypes))
+	for i, t := range config.AllowContentTypes {
+		allowed[i] = strings.ToLower(strings.TrimSpace(t))
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if config.Skipper(c) {
+				return next(c)
+			}
+
+			req := c.Request()
+			ctype := req.Header.Get(echo.HeaderContentType)
+			if ctype == "" {
+				if req.ContentLength == 0 {
+					return next(c)
+				}
+				switch req.Method {
+				case http.MethodPost, http.MethodPut, http.MethodPatch:
+					return echo.NewHTTPError(http.StatusUnsupportedMediaType, "missing content type")
+				}
+				return next(c)
+			}
+
+			mediaType, _, err := mime.ParseMediaType(ctype)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusUnsupportedMediaType, "invalid content type").SetInternal(err)
+			}
+			for _, a := range allowed {
+				if matchMediaType(mediaType, a) {
+					return next(c)
+				}
+			}
+			return echo.NewHTTPError(http.StatusUnsupportedMediaType, "unsupported content type "+mediaType)
+		}
+	}
+}
+
+// matchMediaType compares media type with pattern, where pattern may use `*`
+// as a wildcard type or subtype.
+func matchMediaType(mediaType, pattern string) bool {
+	if pattern == "*" || pattern == "*/*" || pattern == mediaType {
+		return true
+	}
+	i := strings.Index(pattern, "/")
+	j := strings.Index(mediaType, "/")
+	if i == -1 || j == -1 {
+		return false
+	}
+	return pattern[i+1:] == "*" && pattern[:i] == mediaType[:j]
+}
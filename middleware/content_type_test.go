@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContentType(t *testing.T) {
+	e := echo.New()
+	h := ContentType(echo.MIMEApplicationJSON, "text/*")(func(c echo.Context) error {
+		return c.String(http.StatusOK, "test")
+	})
+
+	assert := assert.New(t)
+
+	// Allowed content type with parameters
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	if assert.NoError(h(c)) {
+		assert.Equal(http.StatusOK, rec.Code)
+	}
+
+	// Allowed by wildcard
+	req = httptest.NewRequest(http.MethodPut, "/", strings.NewReader("test"))
+	req.Header.Set(echo.HeaderContentType, echo.MIMETextPlain)
+	c = e.NewContext(req, httptest.NewRecorder())
+	assert.NoError(h(c))
+
+	// Disallowed content type
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader("<a/>"))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationXML)
+	c = e.NewContext(req, httptest.NewRecorder())
+	he := h(c).(*echo.HTTPError)
+	assert.Equal(http.StatusUnsupportedMediaType, he.Code)
+
+	// Malformed content type
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader("test"))
+	req.Header.Set(echo.HeaderContentType, "text/plain; charset")
+	c = e.NewContext(req, httptest.NewRecorder())
+	he = h(c).(*echo.HTTPError)
+	assert.Equal(http.StatusUnsupportedMediaType, he.Code)
+
+	// Missing content type on a method with a body
+	req = httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(`{}`))
+	c = e.NewContext(req, httptest.NewRecorder())
+	he = h(c).(*echo.HTTPError)
+	assert.Equal(http.StatusUnsupportedMediaType, he.Code)
+
+	// Missing content type on a method without a body
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	c = e.NewContext(req, httptest.NewRecorder())
+	assert.NoError(h(c))
+
+	// Missing content type on a request without a body
+	req = httptest.NewRequest(http.MethodPost, "/", nil)
+	c = e.NewContext(req, httptest.NewRecorder())
+	assert.NoError(h(c))
+}
+
+func TestMatchMediaType(t *testing.T) {
+	assert := assert.New(t)
+	assert.True(matchMediaType("application/json", "application/json"))
+	assert.True(matchMediaType("application/json", "application/*"))
+	assert.True(matchMediaType("application/json", "*/*"))
+	assert.False(matchMediaType("application/json", "text/*"))
+	assert.False(matchMediaType("application/json", "application/xml"))
+}
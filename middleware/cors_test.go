@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -83,3 +84,35 @@ func TestCORS(t *testing.T) {
 	h(c)
 	assert.Equal(t, "http://bbb.example.com", rec.Header().Get(echo.HeaderAccessControlAllowOrigin))
 }
+
+func TestCORSAllowOriginFunc(t *testing.T) {
+	e := echo.New()
+	errLookup := errors.New("lookup failed")
+	h := CORSWithConfig(CORSConfig{
+		AllowOrigins: []string{"*"},
+		AllowOriginFunc: func(origin string) (bool, error) {
+			if origin == "http://fail.com" {
+				return false, errLookup
+			}
+			return origin == "http://allowed.com", nil
+		},
+	})(func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	for origin, allowOrigin := range map[string]string{
+		"http://allowed.com": "http://allowed.com",
+		"http://other.com":   "",
+	} {
+		req := httptest.NewRequest(http.MethodOptions, "/", nil)
+		req.Header.Set(echo.HeaderOrigin, origin)
+		rec := httptest.NewRecorder()
+		assert.NoError(t, h(e.NewContext(req, rec)))
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		assert.Equal(t, allowOrigin, rec.Header().Get(echo.HeaderAccessControlAllowOrigin), origin)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(echo.HeaderOrigin, "http://fail.com")
+	assert.Equal(t, errLookup, h(e.NewContext(req, httptest.NewRecorder())))
+}
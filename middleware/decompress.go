@@ -0,0 +1,144 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/bytes"
+)
+
+type (
+	// DecompressConfig defines the config for Decompress middleware.
+	DecompressConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// Maximum allowed size for the decompressed request body, it can be
+		// specified as `4x` or `4xB`, where x is one of the multiple from K, M,
+		// G, T or P.
+		// Optional. Default value "", which means no limit.
+		Limit string `yaml:"limit"`
+	}
+
+	decompressedBody struct {
+		io.Reader
+		decompressor io.Closer
+		body         io.Closer
+	}
+
+	// decompressLimitReader fails every read once more than the limit was
+	// decompressed, without returning any of the data over the limit.
+	decompressLimitReader struct {
+		reader    io.Reader
+		remaining int64
+		exceeded  bool
+	}
+)
+
+const (
+	deflateScheme = "deflate"
+)
+
+var (
+	// DefaultDecompressConfig is the default Decompress middleware config.
+	DefaultDecompressConfig = DecompressConfig{
+		Skipper: DefaultSkipper,
+	}
+)
+
+// Decompress returns a Decompress middleware.
+//
+// Decompress middleware decompresses request bodies sent with a gzip or deflate
+// `Content-Encoding`.
+func Decompress() echo.MiddlewareFunc {
+	return DecompressWithConfig(DefaultDecompressConfig)
+}
+
+// DecompressWithConfig returns a Decompress middleware with config.
+// See: `Decompress()`.
+//
+// If a limit is configured, it is enforced on the decompressed stream, so a
+// small compressed payload expanding past the limit makes reading the body fail
+// with "413 - Request Entity Too Large", regardless of the compressed size.
+func DecompressWithConfig(config DecompressConfig) echo.MiddlewareFunc {
+	// Defaults
+	if config.Skipper == nil {
+		config.Skipper = DefaultDecompressConfig.Skipper
+	}
+
+	limit := int64(-1)
+	if config.Limit != "" {
+		l, err := bytes.Parse(config.Limit)
+		if err != nil {
+			panic(fmt.Errorf("echo: invalid decompress limit=%s", config.Limit))
+		}
+		limit = l
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if config.Skipper(c) {
+				return next(c)
+			}
+
+			req := c.Request()
+			var (
+				d   io.ReadCloser
+				err error
+			)
+			switch strings.ToLower(strings.TrimSpace(req.Header.Get(echo.HeaderContentEncoding))) {
+			case gzipScheme:
+				d, err = gzip.NewReader(req.Body)
+			case deflateScheme:
+				d, err = zlib.NewReader(req.Body)
+			default:
+				return next(c)
+			}
+			if err != nil {
+				if err == io.EOF {
+					return next(c)
+				}
+				return echo.NewHTTPError(http.StatusBadRequest, "invalid compressed request body").SetInternal(err)
+			}
+
+			var r io.Reader = d
+			if limit >= 0 {
+				r = &decompressLimitReader{reader: d, remaining: limit}
+			}
+			req.Body = &decompressedBody{Reader: r, decompressor: d, body: req.Body}
+			req.Header.Del(echo.HeaderContentEncoding)
+			req.Header.Del(echo.HeaderContentLength)
+			req.ContentLength = -1
+			return next(c)
+		}
+	}
+}
+
+func (b *decompressedBody) Close() error {
+	err := b.decompressor.Close()
+	if cerr := b.body.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+func (r *decompressLimitReader) Read(p []byte) (n int, err error) {
+	if r.exceeded {
+		return 0, echo.ErrStatusRequestEntityTooLarge
+	}
+	if int64(len(p)) > r.remaining+1 {
+		p = p[:r.remaining+1]
+	}
+	n, err = r.reader.Read(p)
+	if int64(n) > r.remaining {
+		r.exceeded = true
+		return 0, echo.ErrStatusRequestEntityTooLarge
+	}
+	r.remaining -= int64(n)
+	return
+}
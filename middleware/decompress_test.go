@@ -0,0 +1,125 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecompress(t *testing.T) {
+	e := echo.New()
+	h := Decompress()(func(c echo.Context) error {
+		b, err := ioutil.ReadAll(c.Request().Body)
+		if err != nil {
+			return err
+		}
+		return c.String(http.StatusOK, string(b))
+	})
+
+	assert := assert.New(t)
+
+	// gzip
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(gzipBytes(t, []byte("test"))))
+	req.Header.Set(echo.HeaderContentEncoding, gzipScheme)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	if assert.NoError(h(c)) {
+		assert.Equal("test", rec.Body.String())
+		assert.Empty(req.Header.Get(echo.HeaderContentEncoding))
+	}
+
+	// deflate
+	buf := new(bytes.Buffer)
+	w := zlib.NewWriter(buf)
+	w.Write([]byte("test"))
+	w.Close()
+	req = httptest.NewRequest(http.MethodPost, "/", buf)
+	req.Header.Set(echo.HeaderContentEncoding, deflateScheme)
+	rec = httptest.NewRecorder()
+	c = e.NewContext(req, rec)
+	if assert.NoError(h(c)) {
+		assert.Equal("test", rec.Body.String())
+	}
+
+	// Uncompressed
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader("test"))
+	rec = httptest.NewRecorder()
+	c = e.NewContext(req, rec)
+	if assert.NoError(h(c)) {
+		assert.Equal("test", rec.Body.String())
+	}
+
+	// Invalid
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader("test"))
+	req.Header.Set(echo.HeaderContentEncoding, gzipScheme)
+	c = e.NewContext(req, httptest.NewRecorder())
+	he := h(c).(*echo.HTTPError)
+	assert.Equal(http.StatusBadRequest, he.Code)
+}
+
+func TestDecompressLimit(t *testing.T) {
+	e := echo.New()
+	h := DecompressWithConfig(DecompressConfig{Limit: "16K"})(func(c echo.Context) error {
+		b, err := ioutil.ReadAll(c.Request().Body)
+		if err != nil {
+			return err
+		}
+		return c.String(http.StatusOK, string(b))
+	})
+
+	assert := assert.New(t)
+
+	// Within limit
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(gzipBytes(t, []byte("test"))))
+	req.Header.Set(echo.HeaderContentEncoding, gzipScheme)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	if assert.NoError(h(c)) {
+		assert.Equal("test", rec.Body.String())
+	}
+
+	// Decompression bomb
+	body := gzipBytes(t, make([]byte, 1<<20))
+	assert.True(len(body) < 16<<10)
+	req = httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+	req.Header.Set(echo.HeaderContentEncoding, gzipScheme)
+	c = e.NewContext(req, httptest.NewRecorder())
+	he := h(c).(*echo.HTTPError)
+	assert.Equal(http.StatusRequestEntityTooLarge, he.Code)
+
+	// Decompression bomb bound by the handler
+	e.POST("/", func(c echo.Context) error {
+		var v interface{}
+		if err := c.Bind(&v); err != nil {
+			return err
+		}
+		return c.NoContent(http.StatusOK)
+	}, DecompressWithConfig(DecompressConfig{Limit: "16K"}))
+	json := `{"a":"` + strings.Repeat("a", 1<<20) + `"}`
+	req = httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(gzipBytes(t, []byte(json))))
+	req.Header.Set(echo.HeaderContentEncoding, gzipScheme)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(http.StatusRequestEntityTooLarge, rec.Code)
+}
+
+func gzipBytes(t *testing.T, b []byte) []byte {
+	buf := new(bytes.Buffer)
+	w := gzip.NewWriter(buf)
+	if _, err := w.Write(b); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	// DeprecationConfig defines the config for Deprecation middleware.
+	DeprecationConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// Date is the time the endpoints were or will be deprecated.
+		// Required.
+		Date time.Time `yaml:"date"`
+
+		// Sunset is the time the endpoints become unresponsive.
+		// Optional.
+		Sunset time.Time `yaml:"sunset"`
+
+		// Link is the URL of the migration documentation.
+		// Optional.
+		Link string `yaml:"link"`
+	}
+)
+
+var (
+	// DefaultDeprecationConfig is the default Deprecation middleware config.
+	DefaultDeprecationConfig = DeprecationConfig{
+		Skipper: DefaultSkipper,
+	}
+)
+
+// Deprecation returns a Deprecation middleware.
+//
+// Deprecation middleware marks all the endpoints it is used for, e.g. a whole
+// `/v1` group, as deprecated with the `Deprecation`, `Sunset` and `Link` response
+// headers. See `Context#Deprecation()`.
+func Deprecation(date, sunset time.Time, link string) echo.MiddlewareFunc {
+	c := DefaultDeprecationConfig
+	c.Date = date
+	c.Sunset = sunset
+	c.Link = link
+	return DeprecationWithConfig(c)
+}
+
+// DeprecationWithConfig returns a Deprecation middleware with config.
+// See: `Deprecation()`.
+func DeprecationWithConfig(config DeprecationConfig) echo.MiddlewareFunc {
+	// Defaults
+	if config.Skipper == nil {
+		config.Skipper = DefaultDeprecationConfig.Skipper
+	}
+	if config.Date.IsZero() {
+		panic("echo: deprecation middleware requires a date")
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if !config.Skipper(c) {
+				c.Deprecation(config.Date, config.Sunset, config.Link)
+			}
+			return next(c)
+		}
+	}
+}
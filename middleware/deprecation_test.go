@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeprecation(t *testing.T) {
+	e := echo.New()
+	date := time.Date(2029, time.January, 1, 0, 0, 0, 0, time.UTC)
+	sunset := time.Date(2030, time.June, 1, 12, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+	v1 := e.Group("/v1", Deprecation(date, sunset, "https://example.com/migrate"))
+	v1.GET("/users", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+	e.GET("/v2/users", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/users", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, "@1861920000", rec.Header().Get(echo.HeaderDeprecation))
+	assert.Equal(t, "Sat, 01 Jun 2030 10:00:00 GMT", rec.Header().Get(echo.HeaderSunset))
+	assert.Equal(t, `<https://example.com/migrate>; rel="deprecation"`, rec.Header().Get(echo.HeaderLink))
+
+	req = httptest.NewRequest(http.MethodGet, "/v2/users", nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Empty(t, rec.Header().Get(echo.HeaderDeprecation))
+
+	// Without sunset and link
+	h := DeprecationWithConfig(DeprecationConfig{Date: date})(func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+	rec = httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+	assert.NoError(t, h(c))
+	assert.Equal(t, "@1861920000", rec.Header().Get(echo.HeaderDeprecation))
+	assert.Empty(t, rec.Header().Get(echo.HeaderSunset))
+	assert.Empty(t, rec.Header().Get(echo.HeaderLink))
+
+	// Without date
+	assert.Panics(t, func() {
+		DeprecationWithConfig(DeprecationConfig{})
+	})
+}
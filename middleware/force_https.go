@@ -0,0 +1,118 @@
+package middleware
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	// ForceHTTPSConfig defines the config for ForceHTTPS middleware.
+	ForceHTTPSConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// Reject makes the middleware reject HTTP requests with "403 - Forbidden"
+		// instead of redirecting them.
+		// Optional. Default value false.
+		Reject bool `yaml:"reject"`
+
+		// ExemptPaths is a list of paths allowed over HTTP, e.g. for ACME
+		// challenges or health checks. A path ending with `*` matches all the
+		// paths with that prefix.
+		// Optional.
+		ExemptPaths []string `yaml:"exempt_paths"`
+
+		// TrustedProxies is a list of IP addresses or CIDR ranges of the
+		// TLS-terminating proxies whose `X-Forwarded-Proto` and similar headers
+		// are trusted to tell the scheme of a request. The headers of other
+		// clients are ignored.
+		// Optional.
+		TrustedProxies []string `yaml:"trusted_proxies"`
+	}
+)
+
+var (
+	// DefaultForceHTTPSConfig is the default ForceHTTPS middleware config.
+	DefaultForceHTTPSConfig = ForceHTTPSConfig{
+		Skipper: DefaultSkipper,
+	}
+)
+
+// ForceHTTPS returns a ForceHTTPS middleware.
+//
+// ForceHTTPS middleware redirects HTTP requests to the HTTPS equivalent with
+// "308 - Permanent Redirect", which preserves the method and body of the
+// request, or rejects them in reject mode.
+//
+// Usage `Echo#Pre(ForceHTTPS())`
+func ForceHTTPS() echo.MiddlewareFunc {
+	return ForceHTTPSWithConfig(DefaultForceHTTPSConfig)
+}
+
+// ForceHTTPSWithConfig returns a ForceHTTPS middleware with config.
+// See: `ForceHTTPS()`.
+func ForceHTTPSWithConfig(config ForceHTTPSConfig) echo.MiddlewareFunc {
+	// Defaults
+	if config.Skipper == nil {
+		config.Skipper = DefaultForceHTTPSConfig.Skipper
+	}
+
+	trusted := make([]*net.IPNet, len(config.TrustedProxies))
+	for i, p := range config.TrustedProxies {
+		if !strings.Contains(p, "/") {
+			if ip := net.ParseIP(p); ip != nil && ip.To4() != nil {
+				p += "/32"
+			} else {
+				p += "/128"
+			}
+		}
+		_, n, err := net.ParseCIDR(p)
+		if err != nil {
+			panic(fmt.Errorf("echo: invalid trusted proxy=%s", config.TrustedProxies[i]))
+		}
+		trusted[i] = n
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if config.Skipper(c) || isHTTPS(c, trusted) {
+				return next(c)
+			}
+
+			req := c.Request()
+			for _, p := range config.ExemptPaths {
+				if req.URL.Path == p || strings.HasSuffix(p, "*") && strings.HasPrefix(req.URL.Path, p[:len(p)-1]) {
+					return next(c)
+				}
+			}
+
+			if config.Reject {
+				return echo.NewHTTPError(http.StatusForbidden, "HTTPS required")
+			}
+			return c.Redirect(http.StatusPermanentRedirect, "https://"+req.Host+req.URL.RequestURI())
+		}
+	}
+}
+
+// isHTTPS reports whether the request was made over HTTPS, either directly or
+// through one of the trusted proxies.
+func isHTTPS(c echo.Context, trusted []*net.IPNet) bool {
+	if c.IsTLS() {
+		return true
+	}
+	host, _, err := net.SplitHostPort(c.Request().RemoteAddr)
+	if err != nil {
+		return false
+	}
+	ip := net.ParseIP(host)
+	for _, n := range trusted {
+		if n.Contains(ip) {
+			return c.Scheme() == "https"
+		}
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForceHTTPS(t *testing.T) {
+	e := echo.New()
+	next := func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	}
+	config := ForceHTTPSConfig{
+		ExemptPaths:    []string{"/health", "/.well-known/acme-challenge/*"},
+		TrustedProxies: []string{"10.0.0.0/8", "::1"},
+	}
+	serve := func(h echo.HandlerFunc, req *http.Request) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		if err := h(c); err != nil {
+			e.HTTPErrorHandler(err, c)
+		}
+		return rec
+	}
+
+	assert := assert.New(t)
+	redirect := ForceHTTPSWithConfig(config)(next)
+	config.Reject = true
+	reject := ForceHTTPSWithConfig(config)(next)
+
+	// Direct HTTP
+	req := httptest.NewRequest(http.MethodPost, "http://labstack.com/users?id=1", nil)
+	rec := serve(redirect, req)
+	assert.Equal(http.StatusPermanentRedirect, rec.Code)
+	assert.Equal("https://labstack.com/users?id=1", rec.Header().Get(echo.HeaderLocation))
+	rec = serve(reject, req)
+	assert.Equal(http.StatusForbidden, rec.Code)
+
+	// Direct HTTPS
+	req = httptest.NewRequest(http.MethodGet, "https://labstack.com/", nil)
+	req.TLS = &tls.ConnectionState{}
+	assert.Equal(http.StatusOK, serve(redirect, req).Code)
+	assert.Equal(http.StatusOK, serve(reject, req).Code)
+
+	// Proxied HTTPS from a trusted proxy
+	req = httptest.NewRequest(http.MethodGet, "http://labstack.com/", nil)
+	req.RemoteAddr = "10.1.2.3:1234"
+	req.Header.Set(echo.HeaderXForwardedProto, "https")
+	assert.Equal(http.StatusOK, serve(redirect, req).Code)
+	assert.Equal(http.StatusOK, serve(reject, req).Code)
+	req.RemoteAddr = "[::1]:1234"
+	assert.Equal(http.StatusOK, serve(reject, req).Code)
+
+	// Proxied HTTP from a trusted proxy
+	req.Header.Set(echo.HeaderXForwardedProto, "http")
+	assert.Equal(http.StatusPermanentRedirect, serve(redirect, req).Code)
+	assert.Equal(http.StatusForbidden, serve(reject, req).Code)
+
+	// Forwarded header from an untrusted client
+	req = httptest.NewRequest(http.MethodGet, "http://labstack.com/", nil)
+	req.RemoteAddr = "192.168.1.1:1234"
+	req.Header.Set(echo.HeaderXForwardedProto, "https")
+	assert.Equal(http.StatusPermanentRedirect, serve(redirect, req).Code)
+	assert.Equal(http.StatusForbidden, serve(reject, req).Code)
+
+	// Exempt paths
+	req = httptest.NewRequest(http.MethodGet, "http://labstack.com/health", nil)
+	assert.Equal(http.StatusOK, serve(reject, req).Code)
+	req = httptest.NewRequest(http.MethodGet, "http://labstack.com/.well-known/acme-challenge/token", nil)
+	assert.Equal(http.StatusOK, serve(reject, req).Code)
+
+	assert.Panics(func() {
+		ForceHTTPSWithConfig(ForceHTTPSConfig{TrustedProxies: []string{"invalid"}})
+	})
+}
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	// FramingConfig defines the config for Framing middleware.
+	FramingConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+	}
+)
+
+var (
+	// DefaultFramingConfig is the default Framing middleware config.
+	DefaultFramingConfig = FramingConfig{
+		Skipper: DefaultSkipper,
+	}
+)
+
+// Framing returns a Framing middleware.
+//
+// Framing middleware rejects requests with ambiguous message framing, which is
+// a known request smuggling vector behind some proxies, with "400 - Bad
+// Request" response. A request is rejected if it has both `Content-Length` and
+// `Transfer-Encoding` headers, or multiple `Content-Length` headers with
+// different values. It should be registered with `Echo#Pre()` so that requests
+// are rejected before routing.
+//
+// Note that `net/http` server already rejects conflicting `Content-Length`
+// headers and drops `Content-Length` from chunked requests while parsing them.
+// This middleware guards handlers served by other servers, or receiving
+// requests rebuilt from forwarded headers.
+func Framing() echo.MiddlewareFunc {
+	return FramingWithConfig(DefaultFramingConfig)
+}
+
+// FramingWithConfig returns a Framing middleware with config.
+// See: `Framing()`.
+func FramingWithConfig(config FramingConfig) echo.MiddlewareFunc {
+	// Defaults
+	if config.Skipper == nil {
+		config.Skipper = DefaultFramingConfig.Skipper
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if config.Skipper(c) {
+				return next(c)
+			}
+
+			req := c.Request()
+			lengths := req.Header[echo.HeaderContentLength]
+			for _, l := range lengths {
+				if l != lengths[0] {
+					return echo.NewHTTPError(http.StatusBadRequest, "conflicting Content-Length headers")
+				}
+			}
+			if len(lengths) > 0 && (len(req.TransferEncoding) > 0 || len(req.Header[echo.HeaderTransferEncoding]) > 0) {
+				return echo.NewHTTPError(http.StatusBadRequest, "both Content-Length and Transfer-Encoding headers present")
+			}
+			return next(c)
+		}
+	}
+}
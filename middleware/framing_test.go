@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFraming(t *testing.T) {
+	e := echo.New()
+	h := Framing()(func(c echo.Context) error {
+		return c.String(http.StatusOK, "test")
+	})
+
+	assert := assert.New(t)
+
+	// Valid
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("test"))
+	req.Header.Set(echo.HeaderContentLength, "4")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	if assert.NoError(h(c)) {
+		assert.Equal(http.StatusOK, rec.Code)
+	}
+
+	// Conflicting Content-Length
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader("test"))
+	req.Header[echo.HeaderContentLength] = []string{"4", "0"}
+	c = e.NewContext(req, httptest.NewRecorder())
+	he := h(c).(*echo.HTTPError)
+	assert.Equal(http.StatusBadRequest, he.Code)
+
+	// Content-Length and Transfer-Encoding
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader("test"))
+	req.Header.Set(echo.HeaderContentLength, "4")
+	req.TransferEncoding = []string{"chunked"}
+	c = e.NewContext(req, httptest.NewRecorder())
+	he = h(c).(*echo.HTTPError)
+	assert.Equal(http.StatusBadRequest, he.Code)
+}
+
+func TestFramingPre(t *testing.T) {
+	e := echo.New()
+	e.Pre(Framing())
+	e.POST("/", func(c echo.Context) error {
+		return c.String(http.StatusOK, "test")
+	})
+
+	assert := assert.New(t)
+
+	// Transfer-Encoding and Content-Length headers, as received from a
+	// server which doesn't normalize them
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("test"))
+	req.Header.Set(echo.HeaderTransferEncoding, "chunked")
+	req.Header.Set(echo.HeaderContentLength, "4")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(http.StatusBadRequest, rec.Code)
+	assert.Contains(rec.Body.String(), "both Content-Length and Transfer-Encoding headers present")
+
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader("test"))
+	req.Header.Set(echo.HeaderContentLength, "4")
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(http.StatusOK, rec.Code)
+}
@@ -33,6 +33,7 @@ type (
 		// - host
 		// - method
 		// - path
+		// - route_name (Matched route name, or its path if unnamed)
 		// - protocol
 		// - referer
 		// - user_agent
@@ -154,6 +155,8 @@ func LoggerWithConfig(config LoggerConfig) echo.MiddlewareFunc {
 						p = "/"
 					}
 					return buf.WriteString(p)
+				case "route_name":
+					return buf.WriteString(c.RouteName())
 				case "protocol":
 					return buf.WriteString(req.Proto)
 				case "referer":
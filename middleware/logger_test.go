@@ -171,3 +171,28 @@ func TestLoggerCustomTimestamp(t *testing.T) {
 	_, err := time.Parse(customTimeFormat, loggedTime)
 	assert.Error(t, err)
 }
+
+func TestLoggerRouteName(t *testing.T) {
+	buf := new(bytes.Buffer)
+	e := echo.New()
+	e.Use(LoggerWithConfig(LoggerConfig{
+		Format: "${route_name}\n",
+		Output: buf,
+	}))
+	r := e.GET("/users/:id", func(c echo.Context) error {
+		return c.String(http.StatusOK, "test")
+	})
+	r.Name = "get-user"
+	e.GET("/posts/:id", func(c echo.Context) error {
+		return c.String(http.StatusOK, "test")
+	}).Name = ""
+
+	req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	e.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Equal(t, "get-user\n", buf.String())
+
+	buf.Reset()
+	req = httptest.NewRequest(http.MethodGet, "/posts/1", nil)
+	e.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Equal(t, "/posts/:id\n", buf.String())
+}
@@ -0,0 +1,168 @@
+package middleware
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	// ParamLimitConfig defines the config for ParamLimit middleware.
+	ParamLimitConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// MaxQueryParams is the maximum number of query parameter values allowed.
+		// Values of repeated keys are counted individually.
+		// Optional. Default value 0, which means no limit.
+		MaxQueryParams int `yaml:"max_query_params"`
+
+		// MaxFormParams is the maximum number of form field values allowed in
+		// `application/x-www-form-urlencoded` bodies, or parts, including files,
+		// in `multipart/form-data` bodies. Values of repeated keys are counted
+		// individually.
+		// Optional. Default value 0, which means no limit.
+		MaxFormParams int `yaml:"max_form_params"`
+	}
+)
+
+var (
+	// DefaultParamLimitConfig is the default ParamLimit middleware config.
+	DefaultParamLimitConfig = ParamLimitConfig{
+		Skipper: DefaultSkipper,
+	}
+)
+
+// ParamLimit returns a ParamLimit middleware.
+//
+// ParamLimit middleware limits the number of query parameters and form fields
+// of a request, to defend against parameter pollution. They are counted before
+// the query or the body is parsed, so requests exceeding a limit don't cost
+// parsing them. If a limit is exceeded, it sends "400 - Bad Request" response.
+func ParamLimit(limit int) echo.MiddlewareFunc {
+	c := DefaultParamLimitConfig
+	c.MaxQueryParams = limit
+	c.MaxFormParams = limit
+	return ParamLimitWithConfig(c)
+}
+
+// ParamLimitWithConfig returns a ParamLimit middleware with config.
+// See: `ParamLimit()`.
+func ParamLimitWithConfig(config ParamLimitConfig) echo.MiddlewareFunc {
+	// Defaults
+	if config.Skipper == nil {
+		config.Skipper = DefaultParamLimitConfig.Skipper
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if config.Skipper(c) {
+				return next(c)
+			}
+
+			req := c.Request()
+			if config.MaxQueryParams > 0 {
+				pc := new(paramCounter)
+				pc.write([]byte(req.URL.RawQuery))
+				if pc.n > config.MaxQueryParams {
+					return echo.NewHTTPError(http.StatusBadRequest,
+						fmt.Sprintf("too many query parameters, limit is %d", config.MaxQueryParams))
+				}
+			}
+
+			ctype := req.Header.Get(echo.HeaderContentType)
+			if config.MaxFormParams > 0 &&
+				(strings.HasPrefix(ctype, echo.MIMEApplicationForm) || strings.HasPrefix(ctype, echo.MIMEMultipartForm)) {
+				exceeded, err := exceedsFormParams(req, ctype, config.MaxFormParams)
+				if err != nil {
+					return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+				}
+				if exceeded {
+					return echo.NewHTTPError(http.StatusBadRequest,
+						fmt.Sprintf("too many form fields, limit is %d", config.MaxFormParams))
+				}
+			}
+			return next(c)
+		}
+	}
+}
+
+// paramFormScanLimit is the number of bytes of a form body scanned for its
+// fields. The form parser of `net/http` rejects larger urlencoded bodies anyway.
+const paramFormScanLimit = 10 << 20
+
+// paramCounter counts the non-empty params of urlencoded data, separated by
+// `&` or `;`, written to it in chunks.
+type paramCounter struct {
+	n     int
+	field bool
+}
+
+func (pc *paramCounter) write(b []byte) {
+	for _, c := range b {
+		if c == '&' || c == ';' {
+			pc.field = false
+		} else if !pc.field {
+			pc.field = true
+			pc.n++
+		}
+	}
+}
+
+// exceedsFormParams reads the body of req until it has more than max fields
+// or parts, and puts back what it read for the handler to parse.
+func exceedsFormParams(req *http.Request, ctype string, max int) (exceeded bool, err error) {
+	var delim []byte
+	if strings.HasPrefix(ctype, echo.MIMEMultipartForm) {
+		_, params, err := mime.ParseMediaType(ctype)
+		if err != nil {
+			return false, err
+		}
+		if params["boundary"] == "" {
+			return false, http.ErrMissingBoundary
+		}
+		delim = []byte("--" + params["boundary"])
+	}
+
+	buf := new(bytes.Buffer)
+	defer func() {
+		req.Body = &struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(buf.Bytes()), req.Body), req.Body}
+	}()
+	pc := new(paramCounter)
+	r := io.LimitReader(req.Body, paramFormScanLimit)
+	chunk := make([]byte, 32<<10)
+	scanned, delims := 0, 0
+	for !exceeded {
+		n, rerr := r.Read(chunk)
+		buf.Write(chunk[:n])
+		if delim == nil {
+			pc.write(chunk[:n])
+			exceeded = pc.n > max
+		} else {
+			// Delimiters may span chunks, so the end of the data is scanned
+			// again with the next chunk.
+			b := buf.Bytes()
+			delims += bytes.Count(b[scanned:], delim)
+			if s := len(b) - len(delim) + 1; s > scanned {
+				scanned = s
+			}
+			// The last delimiter closes the body.
+			exceeded = delims-1 > max
+		}
+		if rerr == io.EOF {
+			return
+		}
+		if rerr != nil {
+			return false, rerr
+		}
+	}
+	return
+}
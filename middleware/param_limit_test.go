@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParamLimit(t *testing.T) {
+	e := echo.New()
+	h := ParamLimit(3)(func(c echo.Context) error {
+		return c.String(http.StatusOK, "test")
+	})
+
+	assert := assert.New(t)
+
+	// Within limit
+	req := httptest.NewRequest(http.MethodGet, "/?a=1&b=2&b=3", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	if assert.NoError(h(c)) {
+		assert.Equal(http.StatusOK, rec.Code)
+	}
+
+	// Query over limit with repeated keys
+	req = httptest.NewRequest(http.MethodGet, "/?a=1&a=2&a=3&a=4", nil)
+	c = e.NewContext(req, httptest.NewRecorder())
+	he := h(c).(*echo.HTTPError)
+	assert.Equal(http.StatusBadRequest, he.Code)
+	assert.Equal("too many query parameters, limit is 3", he.Message)
+
+	// Query over limit with semicolons, empty params not counted
+	req = httptest.NewRequest(http.MethodGet, "/?a=1&&b=2;c=3;d", nil)
+	c = e.NewContext(req, httptest.NewRecorder())
+	he = h(c).(*echo.HTTPError)
+	assert.Equal(http.StatusBadRequest, he.Code)
+	req = httptest.NewRequest(http.MethodGet, "/?a=1&&b=2;c=3;", nil)
+	c = e.NewContext(req, httptest.NewRecorder())
+	assert.NoError(h(c))
+
+	// Form within limit, query values not counted as form fields, and the
+	// body is still parsed by the handler
+	req = httptest.NewRequest(http.MethodPost, "/?q=1&r=2", strings.NewReader("a=1&b=2&c=3"))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	c = e.NewContext(req, httptest.NewRecorder())
+	if assert.NoError(h(c)) {
+		assert.Equal("3", c.FormValue("c"))
+	}
+
+	// Form over limit
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader("a=1&b=2&c=3&c=4"))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	c = e.NewContext(req, httptest.NewRecorder())
+	he = h(c).(*echo.HTTPError)
+	assert.Equal(http.StatusBadRequest, he.Code)
+	assert.Equal("too many form fields, limit is 3", he.Message)
+
+	// Multipart
+	multipart := func(n int) *http.Request {
+		body := ""
+		for i := 0; i < n; i++ {
+			body += "--xyz\r\nContent-Disposition: form-data; name=\"a\"\r\n\r\n1\r\n"
+		}
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body+"--xyz--\r\n"))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEMultipartForm+"; boundary=xyz")
+		return req
+	}
+	c = e.NewContext(multipart(3), httptest.NewRecorder())
+	if assert.NoError(h(c)) {
+		f, err := c.FormParams()
+		if assert.NoError(err) {
+			assert.Len(f["a"], 3)
+		}
+	}
+	c = e.NewContext(multipart(4), httptest.NewRecorder())
+	he = h(c).(*echo.HTTPError)
+	assert.Equal(http.StatusBadRequest, he.Code)
+
+	// No limit
+	h = ParamLimitWithConfig(ParamLimitConfig{MaxFormParams: 1})(func(c echo.Context) error {
+		return c.NoContent(http.StatusNoContent)
+	})
+	req = httptest.NewRequest(http.MethodGet, "/?a=1&a=2&a=3&a=4", nil)
+	c = e.NewContext(req, httptest.NewRecorder())
+	assert.NoError(h(c))
+}
@@ -1,8 +1,10 @@
 package middleware
 
 import (
+	"bytes"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"math/rand"
 	"net"
 	"net/http"
@@ -14,6 +16,7 @@ import (
 	"time"
 
 	"github.com/labstack/echo/v4"
+	gbytes "github.com/labstack/gommon/bytes"
 )
 
 // TODO: Handle TLS proxy
@@ -45,7 +48,46 @@ type (
 		// Examples: If custom TLS certificates are required.
 		Transport http.RoundTripper
 
-		rewriteRegex map[*regexp.Regexp]string
+		// ModifyResponse modifies the response of the upstream target before
+		// it's sent, e.g. to rewrite its headers. Returning an error responds
+		// with "502 - Bad Gateway". Requires Go 1.11.
+		// Optional.
+		ModifyResponse func(*http.Response) error
+
+		// RetryCount is the maximum number of times a failed request is retried
+		// against the next target of the balancer. Retries require Go 1.11.
+		// Optional. Default value 0, which means no retries.
+		RetryCount int `yaml:"retry_count"`
+
+		// RetryStatusCodes is a list of upstream response status codes which
+		// trigger a retry.
+		// Optional. Default value []int{502, 503, 504}.
+		RetryStatusCodes []int `yaml:"retry_status_codes"`
+
+		// RetryFilter reports whether a request failing with the error, e.g. an
+		// unreachable upstream, should be retried.
+		// Optional. Default value retries all errors.
+		RetryFilter func(c echo.Context, err error) bool
+
+		// RetryBackoff is the time to wait before the first retry, which is
+		// doubled for every further retry.
+		// Optional. Default value 0.
+		RetryBackoff time.Duration `yaml:"retry_backoff"`
+
+		// RetryMethods is a list of request methods which are retried.
+		// Optional. Default value idempotent methods GET, HEAD, OPTIONS, TRACE,
+		// PUT and DELETE.
+		RetryMethods []string `yaml:"retry_methods"`
+
+		// RetryBodyLimit is the maximum size of a request body buffered to be
+		// replayed on retry, requests with larger bodies aren't retried. It can
+		// be specified as `4x` or `4xB`, where x is one of the multiple from K,
+		// M, G, T or P.
+		// Optional. Default value "1M".
+		RetryBodyLimit string `yaml:"retry_body_limit"`
+
+		rewriteRegex   map[*regexp.Regexp]string
+		retryBodyLimit int64
 	}
 
 	// ProxyTarget defines the upstream target.
@@ -78,16 +120,30 @@ type (
 		*commonBalancer
 		i uint32
 	}
+
+	// proxyStatusError is the error of an upstream response with a status code
+	// which triggers a retry.
+	proxyStatusError struct {
+		code int
+	}
 )
 
 var (
 	// DefaultProxyConfig is the default Proxy middleware config.
 	DefaultProxyConfig = ProxyConfig{
-		Skipper:    DefaultSkipper,
-		ContextKey: "target",
+		Skipper:          DefaultSkipper,
+		ContextKey:       "target",
+		RetryStatusCodes: []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout},
+		RetryFilter:      func(echo.Context, error) bool { return true },
+		RetryMethods:     []string{http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete},
+		RetryBodyLimit:   "1M",
 	}
 )
 
+func (e *proxyStatusError) Error() string {
+	return fmt.Sprintf("upstream responded with status %d", e.code)
+}
+
 func proxyRaw(t *ProxyTarget, c echo.Context) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		in, _, err := c.Response().Hijack()
@@ -203,6 +259,23 @@ func ProxyWithConfig(config ProxyConfig) echo.MiddlewareFunc {
 	if config.Balancer == nil {
 		panic("echo: proxy middleware requires balancer")
 	}
+	if config.RetryStatusCodes == nil {
+		config.RetryStatusCodes = DefaultProxyConfig.RetryStatusCodes
+	}
+	if config.RetryFilter == nil {
+		config.RetryFilter = DefaultProxyConfig.RetryFilter
+	}
+	if config.RetryMethods == nil {
+		config.RetryMethods = DefaultProxyConfig.RetryMethods
+	}
+	if config.RetryBodyLimit == "" {
+		config.RetryBodyLimit = DefaultProxyConfig.RetryBodyLimit
+	}
+	limit, err := gbytes.Parse(config.RetryBodyLimit)
+	if err != nil {
+		panic(fmt.Errorf("echo: invalid proxy retry body limit=%s", config.RetryBodyLimit))
+	}
+	config.retryBodyLimit = limit
 	config.rewriteRegex = map[*regexp.Regexp]string{}
 
 	// Initialize
@@ -247,7 +320,7 @@ func ProxyWithConfig(config ProxyConfig) echo.MiddlewareFunc {
 				proxyRaw(tgt, c).ServeHTTP(res, req)
 			case req.Header.Get(echo.HeaderAccept) == "text/event-stream":
 			default:
-				proxyHTTP(tgt, c, config).ServeHTTP(res, req)
+				return proxyHTTPWithRetry(tgt, c, config)
 			}
 			if e, ok := c.Get("_error").(error); ok {
 				err = e
@@ -257,3 +330,89 @@ func ProxyWithConfig(config ProxyConfig) echo.MiddlewareFunc {
 		}
 	}
 }
+
+// proxyHTTPWithRetry proxies the request, retrying it against the next target
+// of the balancer if it fails and the config allows retrying it.
+func proxyHTTPWithRetry(tgt *ProxyTarget, c echo.Context, config ProxyConfig) (err error) {
+	req := c.Request()
+	retries, body, err := retryBody(req, config)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+	}
+
+	for attempt := 0; ; attempt++ {
+		if body != nil {
+			req.Body = ioutil.NopCloser(bytes.NewReader(body))
+		}
+		c.Set("_error", nil)
+		proxyHTTP(tgt, c, config, attempt < retries).ServeHTTP(c.Response(), req)
+		e, ok := c.Get("_error").(error)
+		if !ok {
+			return nil
+		}
+		if attempt >= retries || !shouldRetry(c, e, config) {
+			return e
+		}
+
+		// Backoff
+		if config.RetryBackoff > 0 {
+			t := time.NewTimer(config.RetryBackoff << uint(attempt))
+			select {
+			case <-t.C:
+			case <-req.Context().Done():
+				t.Stop()
+				return e
+			}
+		}
+		tgt = config.Balancer.Next(c)
+		c.Set(config.ContextKey, tgt)
+	}
+}
+
+// retryBody returns the number of retries allowed for the request, and its
+// buffered body to be replayed on retry.
+func retryBody(req *http.Request, config ProxyConfig) (int, []byte, error) {
+	if config.RetryCount <= 0 || !containsMethod(config.RetryMethods, req.Method) {
+		return 0, nil, nil
+	}
+	if req.Body == nil || req.Body == http.NoBody {
+		return config.RetryCount, nil, nil
+	}
+	if req.ContentLength > config.retryBodyLimit {
+		return 0, nil, nil
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(req.Body, config.retryBodyLimit+1))
+	if err != nil {
+		return 0, nil, err
+	}
+	if int64(len(body)) > config.retryBodyLimit {
+		// Too large to be replayed, send what was read followed by the rest.
+		req.Body = &readCloser{Reader: io.MultiReader(bytes.NewReader(body), req.Body), Closer: req.Body}
+		return 0, nil, nil
+	}
+	return config.RetryCount, body, nil
+}
+
+func shouldRetry(c echo.Context, err error, config ProxyConfig) bool {
+	if he, ok := err.(*echo.HTTPError); ok && he.Internal != nil {
+		err = he.Internal
+	}
+	if _, ok := err.(*proxyStatusError); ok {
+		return true
+	}
+	return config.RetryFilter(c, err)
+}
+
+func containsMethod(methods []string, method string) bool {
+	for _, m := range methods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
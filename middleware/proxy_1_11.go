@@ -10,14 +10,28 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
-func proxyHTTP(tgt *ProxyTarget, c echo.Context, config ProxyConfig) http.Handler {
+func proxyHTTP(tgt *ProxyTarget, c echo.Context, config ProxyConfig, retry bool) http.Handler {
 	proxy := httputil.NewSingleHostReverseProxy(tgt.URL)
 	proxy.ErrorHandler = func(resp http.ResponseWriter, req *http.Request, err error) {
 		desc := tgt.URL.String()
 		if tgt.Name != "" {
 			desc = fmt.Sprintf("%s(%s)", tgt.Name, tgt.URL.String())
 		}
-		c.Set("_error", echo.NewHTTPError(http.StatusBadGateway, fmt.Sprintf("remote %s unreachable, could not forward: %v", desc, err)))
+		c.Set("_error", echo.NewHTTPError(http.StatusBadGateway, fmt.Sprintf("remote %s unreachable, could not forward: %v", desc, err)).SetInternal(err))
+	}
+	proxy.ModifyResponse = func(res *http.Response) error {
+		if retry {
+			// Responses to be retried are discarded instead of being sent.
+			for _, code := range config.RetryStatusCodes {
+				if res.StatusCode == code {
+					return &proxyStatusError{code: code}
+				}
+			}
+		}
+		if config.ModifyResponse != nil {
+			return config.ModifyResponse(res)
+		}
+		return nil
 	}
 	proxy.Transport = config.Transport
 	return proxy
@@ -9,6 +9,6 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
-func proxyHTTP(t *ProxyTarget, c echo.Context, config ProxyConfig) http.Handler {
+func proxyHTTP(t *ProxyTarget, c echo.Context, config ProxyConfig, retry bool) http.Handler {
 	return httputil.NewSingleHostReverseProxy(t.URL)
 }
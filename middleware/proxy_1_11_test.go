@@ -3,10 +3,15 @@
 package middleware
 
 import (
+	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
@@ -51,3 +56,94 @@ func TestProxy_1_11(t *testing.T) {
 	assert.Equal(t, "/api/users", req.URL.Path)
 	assert.Equal(t, http.StatusBadGateway, rec.Code)
 }
+
+func TestProxyRetry(t *testing.T) {
+	// Setup
+	var calls int32
+	flaky := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer flaky.Close()
+	flakyURL, _ := url.Parse(flaky.URL)
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		fmt.Fprintf(w, "healthy %s", b)
+	}))
+	defer healthy.Close()
+	healthyURL, _ := url.Parse(healthy.URL)
+	unreachableURL, _ := url.Parse("http://127.0.0.1:27123")
+
+	proxy := func(urls ...*url.URL) *echo.Echo {
+		targets := []*ProxyTarget{}
+		for _, u := range urls {
+			targets = append(targets, &ProxyTarget{URL: u})
+		}
+		e := echo.New()
+		e.Use(ProxyWithConfig(ProxyConfig{
+			Balancer:     NewRoundRobinBalancer(targets),
+			RetryCount:   1,
+			RetryBackoff: time.Millisecond,
+		}))
+		return e
+	}
+
+	// Retryable status, succeeds on retry with the body replayed
+	e := proxy(flakyURL, healthyURL)
+	req := httptest.NewRequest(http.MethodPut, "/", strings.NewReader("body"))
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "healthy body", rec.Body.String())
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	// Unreachable upstream, succeeds on retry
+	e = proxy(unreachableURL, healthyURL)
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "healthy ", rec.Body.String())
+
+	// Retries exhausted, last response is sent
+	e = proxy(flakyURL, flakyURL)
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+
+	// Non-idempotent method isn't retried
+	e = proxy(flakyURL, healthyURL)
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader("body"))
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, int32(4), atomic.LoadInt32(&calls))
+}
+
+func TestProxyModifyResponse(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Server", "upstream")
+		fmt.Fprint(w, "ok")
+	}))
+	defer upstream.Close()
+	u, _ := url.Parse(upstream.URL)
+
+	e := echo.New()
+	e.Use(ProxyWithConfig(ProxyConfig{
+		Balancer: NewRoundRobinBalancer([]*ProxyTarget{{URL: u}}),
+		ModifyResponse: func(res *http.Response) error {
+			res.Header.Del("Server")
+			res.Header.Set("X-Proxied", "true")
+			return nil
+		},
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "ok", rec.Body.String())
+	assert.Empty(t, rec.Header().Get("Server"))
+	assert.Equal(t, "true", rec.Header().Get("X-Proxied"))
+}
@@ -25,6 +25,19 @@ type (
 		// DisablePrintStack disables printing stack trace.
 		// Optional. Default value as false.
 		DisablePrintStack bool `yaml:"disable_print_stack"`
+
+		// PanicHandler is called with the error and stack trace of every
+		// recovered panic before the error is handled, e.g. to report it to an
+		// error tracking service.
+		// Optional.
+		PanicHandler func(c echo.Context, err error, stack []byte)
+	}
+
+	// panicError is the error of a recovered panic which includes the stack
+	// trace in its message, for the HTTP error handler in debug mode.
+	panicError struct {
+		error
+		stack []byte
 	}
 )
 
@@ -39,7 +52,9 @@ var (
 )
 
 // Recover returns a middleware which recovers from panics anywhere in the chain
-// and handles the control to the centralized HTTPErrorHandler.
+// and handles the control to the centralized HTTPErrorHandler, so the response
+// is sent like for any other error. In debug mode, the error message includes
+// the stack trace.
 func Recover() echo.MiddlewareFunc {
 	return RecoverWithConfig(DefaultRecoverConfig)
 }
@@ -72,6 +87,12 @@ func RecoverWithConfig(config RecoverConfig) echo.MiddlewareFunc {
 					if !config.DisablePrintStack {
 						c.Logger().Printf("[PANIC RECOVER] %v %s\n", err, stack[:length])
 					}
+					if config.PanicHandler != nil {
+						config.PanicHandler(c, err, stack[:length])
+					}
+					if c.Echo().Debug {
+						err = &panicError{error: err, stack: stack[:length]}
+					}
 					c.Error(err)
 				}
 			}()
@@ -79,3 +100,12 @@ func RecoverWithConfig(config RecoverConfig) echo.MiddlewareFunc {
 		}
 	}
 }
+
+func (e *panicError) Error() string {
+	return fmt.Sprintf("%v\n%s", e.error, e.stack)
+}
+
+// Unwrap returns the recovered error.
+func (e *panicError) Unwrap() error {
+	return e.error
+}
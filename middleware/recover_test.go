@@ -24,3 +24,62 @@ func TestRecover(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, rec.Code)
 	assert.Contains(t, buf.String(), "PANIC RECOVER")
 }
+
+func TestRecoverErrorResponse(t *testing.T) {
+	e := echo.New()
+	e.Logger.SetOutput(new(bytes.Buffer))
+	e.Use(Recover())
+	e.GET("/", func(c echo.Context) error {
+		panic("test")
+	})
+	request := func(accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(echo.HeaderAccept, accept)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// JSON
+	rec := request(echo.MIMEApplicationJSON)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Equal(t, echo.MIMEApplicationJSONCharsetUTF8, rec.Header().Get(echo.HeaderContentType))
+	assert.Equal(t, `{"message":"Internal Server Error"}`+"\n", rec.Body.String())
+
+	// HTML
+	rec = request("text/html,application/xhtml+xml,*/*;q=0.8")
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Equal(t, echo.MIMETextHTMLCharsetUTF8, rec.Header().Get(echo.HeaderContentType))
+	assert.Contains(t, rec.Body.String(), "<h1>500 Internal Server Error</h1>")
+	assert.NotContains(t, rec.Body.String(), "goroutine")
+
+	// Debug
+	e.Debug = true
+	rec = request(echo.MIMEApplicationJSON)
+	assert.Contains(t, rec.Body.String(), `"test\ngoroutine`)
+	rec = request("text/html")
+	assert.Contains(t, rec.Body.String(), "<pre>test\ngoroutine")
+}
+
+func TestRecoverPanicHandler(t *testing.T) {
+	e := echo.New()
+	var (
+		reported error
+		stack    []byte
+	)
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	h := RecoverWithConfig(RecoverConfig{
+		DisablePrintStack: true,
+		PanicHandler: func(c echo.Context, err error, s []byte) {
+			reported, stack = err, s
+		},
+	})(func(c echo.Context) error {
+		panic("test")
+	})
+	h(c)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.EqualError(t, reported, "test")
+	assert.Contains(t, string(stack), "goroutine")
+}
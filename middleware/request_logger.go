@@ -0,0 +1,137 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	// RequestLoggerConfig defines the config for RequestLogger middleware.
+	RequestLoggerConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// LogValuesFunc is called with the values of every request after it
+		// was handled, to log them with any logging library.
+		// Required.
+		LogValuesFunc func(c echo.Context, v RequestLoggerValues) error
+
+		// LogHeaders is a list of request headers whose values are collected
+		// into `RequestLoggerValues#Headers`.
+		// Optional.
+		LogHeaders []string `yaml:"log_headers"`
+	}
+
+	// RequestLoggerValues are the values collected by RequestLogger middleware.
+	RequestLoggerValues struct {
+		// StartTime is the time the request was received.
+		StartTime time.Time
+		// Latency is the duration of handling the request.
+		Latency   time.Duration
+		Protocol  string
+		RemoteIP  string
+		Host      string
+		Method    string
+		URI       string
+		URIPath   string
+		RoutePath string
+		RouteName string
+		// RequestID is the X-Request-ID header of the request or response.
+		RequestID string
+		Referer   string
+		UserAgent string
+		Status    int
+		// Error is the error returned by the handler, if any.
+		Error error
+		// ContentLength is the Content-Length header of the request.
+		ContentLength string
+		// ResponseSize is the number of bytes written to the response body.
+		ResponseSize int64
+		// Headers are the values of the request headers in
+		// `RequestLoggerConfig#LogHeaders`.
+		Headers map[string][]string
+	}
+)
+
+var (
+	// DefaultRequestLoggerConfig is the default RequestLogger middleware config.
+	DefaultRequestLoggerConfig = RequestLoggerConfig{
+		Skipper: DefaultSkipper,
+	}
+)
+
+// RequestLogger returns a middleware which collects the values of every request
+// and passes them to fn, e.g. to log them with a structured logger instead of
+// the format of `Logger()`.
+func RequestLogger(fn func(c echo.Context, v RequestLoggerValues) error) echo.MiddlewareFunc {
+	c := DefaultRequestLoggerConfig
+	c.LogValuesFunc = fn
+	return RequestLoggerWithConfig(c)
+}
+
+// RequestLoggerWithConfig returns a RequestLogger middleware with config.
+// See: `RequestLogger()`.
+func RequestLoggerWithConfig(config RequestLoggerConfig) echo.MiddlewareFunc {
+	// Defaults
+	if config.Skipper == nil {
+		config.Skipper = DefaultRequestLoggerConfig.Skipper
+	}
+	if config.LogValuesFunc == nil {
+		panic("echo: request logger middleware requires log values func")
+	}
+	headers := make([]string, len(config.LogHeaders))
+	for i, h := range config.LogHeaders {
+		headers[i] = http.CanonicalHeaderKey(h)
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if config.Skipper(c) {
+				return next(c)
+			}
+
+			req := c.Request()
+			res := c.Response()
+			start := time.Now()
+			err := next(c)
+			if err != nil {
+				// Lets the error handler send the response to know its status
+				c.Error(err)
+			}
+
+			v := RequestLoggerValues{
+				StartTime:     start,
+				Latency:       time.Since(start),
+				Protocol:      req.Proto,
+				RemoteIP:      c.RealIP(),
+				Host:          req.Host,
+				Method:        req.Method,
+				URI:           req.RequestURI,
+				URIPath:       req.URL.Path,
+				RoutePath:     c.Path(),
+				RouteName:     c.RouteName(),
+				RequestID:     req.Header.Get(echo.HeaderXRequestID),
+				Referer:       req.Referer(),
+				UserAgent:     req.UserAgent(),
+				Status:        res.Status,
+				Error:         err,
+				ContentLength: req.Header.Get(echo.HeaderContentLength),
+				ResponseSize:  res.Size,
+			}
+			if v.RequestID == "" {
+				v.RequestID = res.Header().Get(echo.HeaderXRequestID)
+			}
+			if len(headers) > 0 {
+				v.Headers = make(map[string][]string, len(headers))
+				for _, h := range headers {
+					if values, ok := req.Header[h]; ok {
+						v.Headers[h] = values
+					}
+				}
+			}
+			return config.LogValuesFunc(c, v)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestLogger(t *testing.T) {
+	e := echo.New()
+	var values []RequestLoggerValues
+	e.Use(RequestLoggerWithConfig(RequestLoggerConfig{
+		LogHeaders: []string{"x-custom"},
+		LogValuesFunc: func(c echo.Context, v RequestLoggerValues) error {
+			values = append(values, v)
+			return nil
+		},
+	}))
+	e.GET("/users/:id", func(c echo.Context) error {
+		return c.String(http.StatusOK, "OK")
+	}).Name = "user"
+	e.GET("/fail", func(c echo.Context) error {
+		return echo.NewHTTPError(http.StatusTeapot, "fail")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/users/1?a=1", nil)
+	req.Header.Set(echo.HeaderXRequestID, "id")
+	req.Header.Set("X-Custom", "custom")
+	req.Header.Set("User-Agent", "test")
+	e.ServeHTTP(httptest.NewRecorder(), req)
+	req = httptest.NewRequest(http.MethodGet, "/fail", nil)
+	e.ServeHTTP(httptest.NewRecorder(), req)
+
+	if assert.Len(t, values, 2) {
+		v := values[0]
+		assert.Equal(t, http.MethodGet, v.Method)
+		assert.Equal(t, "/users/1?a=1", v.URI)
+		assert.Equal(t, "/users/1", v.URIPath)
+		assert.Equal(t, "/users/:id", v.RoutePath)
+		assert.Equal(t, "user", v.RouteName)
+		assert.Equal(t, "id", v.RequestID)
+		assert.Equal(t, "test", v.UserAgent)
+		assert.Equal(t, http.StatusOK, v.Status)
+		assert.Equal(t, int64(2), v.ResponseSize)
+		assert.Equal(t, map[string][]string{"X-Custom": {"custom"}}, v.Headers)
+		assert.NoError(t, v.Error)
+		assert.False(t, v.StartTime.IsZero())
+
+		v = values[1]
+		assert.Equal(t, http.StatusTeapot, v.Status)
+		if assert.IsType(t, new(echo.HTTPError), v.Error) {
+			assert.Equal(t, http.StatusTeapot, v.Error.(*echo.HTTPError).Code)
+		}
+		assert.Empty(t, v.Headers)
+	}
+}
+
+func TestRequestLoggerError(t *testing.T) {
+	e := echo.New()
+	errLog := errors.New("log failed")
+	h := RequestLogger(func(c echo.Context, v RequestLoggerValues) error {
+		return errLog
+	})(func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+	assert.Equal(t, errLog, h(c))
+
+	assert.Panics(t, func() {
+		RequestLoggerWithConfig(RequestLoggerConfig{})
+	})
+}
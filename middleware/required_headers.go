@@ -0,0 +1,103 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	// RequiredHeadersConfig defines the config for RequiredHeaders middleware.
+	RequiredHeadersConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// Headers is a list of header names which must be present in the request.
+		// Header names are matched case-insensitively.
+		// Required.
+		Headers []string `yaml:"headers"`
+
+		// Validators is a map of header name to a function validating its value.
+		// Validators are only called for headers which are present.
+		// Optional.
+		Validators map[string]RequiredHeaderValidator
+	}
+
+	// RequiredHeaderValidator defines a function to validate a required header
+	// value. A non-nil error rejects the request.
+	RequiredHeaderValidator func(string, echo.Context) error
+)
+
+var (
+	// DefaultRequiredHeadersConfig is the default RequiredHeaders middleware config.
+	DefaultRequiredHeadersConfig = RequiredHeadersConfig{
+		Skipper: DefaultSkipper,
+	}
+)
+
+// RequiredHeaders returns a RequiredHeaders middleware.
+//
+// RequiredHeaders middleware checks that all the provided headers are present
+// in the request before calling the next handler. If any header is missing, it
+// sends "400 - Bad Request" response listing the missing headers.
+func RequiredHeaders(headers ...string) echo.MiddlewareFunc {
+	c := DefaultRequiredHeadersConfig
+	c.Headers = headers
+	return RequiredHeadersWithConfig(c)
+}
+
+// RequiredHeadersWithConfig returns a RequiredHeaders middleware with config.
+// See: `RequiredHeaders()`.
+func RequiredHeadersWithConfig(config RequiredHeadersConfig) echo.MiddlewareFunc {
+	// Defaults
+	if config.Skipper == nil {
+		config.Skipper = DefaultRequiredHeadersConfig.Skipper
+	}
+	if len(config.Headers) == 0 {
+		panic("echo: required-headers middleware requires at least one header")
+	}
+
+	// Initialize
+	headers := make([]string, len(config.Headers))
+	for i, h := range config.Headers {
+		headers[i] = http.CanonicalHeaderKey(h)
+	}
+	validators := make(map[string]RequiredHeaderValidator, len(config.Validators))
+	for h, v := range config.Validators {
+		validators[http.CanonicalHeaderKey(h)] = v
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if config.Skipper(c) {
+				return next(c)
+			}
+
+			header := c.Request().Header
+			missing := []string{}
+			for _, h := range headers {
+				if header.Get(h) == "" {
+					missing = append(missing, h)
+				}
+			}
+			if len(missing) > 0 {
+				return echo.NewHTTPError(http.StatusBadRequest,
+					"missing required headers: "+strings.Join(missing, ", "))
+			}
+
+			for _, h := range headers {
+				v, ok := validators[h]
+				if !ok {
+					continue
+				}
+				if err := v(header.Get(h), c); err != nil {
+					return echo.NewHTTPError(http.StatusBadRequest,
+						fmt.Sprintf("invalid header %s: %v", h, err)).SetInternal(err)
+				}
+			}
+			return next(c)
+		}
+	}
+}
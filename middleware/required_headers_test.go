@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequiredHeaders(t *testing.T) {
+	e := echo.New()
+	h := RequiredHeadersWithConfig(RequiredHeadersConfig{
+		Headers: []string{"idempotency-key", "X-Tenant"},
+		Validators: map[string]RequiredHeaderValidator{
+			"X-TENANT": func(v string, c echo.Context) error {
+				if v != "acme" {
+					return errors.New("unknown tenant")
+				}
+				return nil
+			},
+		},
+	})(func(c echo.Context) error {
+		return c.String(http.StatusOK, "test")
+	})
+
+	assert := assert.New(t)
+
+	// Missing headers
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	he := h(c).(*echo.HTTPError)
+	assert.Equal(http.StatusBadRequest, he.Code)
+	assert.Equal("missing required headers: Idempotency-Key, X-Tenant", he.Message)
+
+	// One header missing
+	req.Header.Set("Idempotency-Key", "abc")
+	he = h(c).(*echo.HTTPError)
+	assert.Equal(http.StatusBadRequest, he.Code)
+	assert.Equal("missing required headers: X-Tenant", he.Message)
+
+	// Invalid header value
+	req.Header.Set("X-Tenant", "other")
+	he = h(c).(*echo.HTTPError)
+	assert.Equal(http.StatusBadRequest, he.Code)
+	assert.Equal("invalid header X-Tenant: unknown tenant", he.Message)
+
+	// All headers present and valid
+	req.Header.Set("X-Tenant", "acme")
+	if assert.NoError(h(c)) {
+		assert.Equal(http.StatusOK, rec.Code)
+		assert.Equal("test", rec.Body.String())
+	}
+
+	// Skipper
+	h = RequiredHeadersWithConfig(RequiredHeadersConfig{
+		Skipper: func(echo.Context) bool { return true },
+		Headers: []string{"Idempotency-Key"},
+	})(func(c echo.Context) error {
+		return c.NoContent(http.StatusNoContent)
+	})
+	req = httptest.NewRequest(http.MethodPost, "/", nil)
+	rec = httptest.NewRecorder()
+	c = e.NewContext(req, rec)
+	assert.NoError(h(c))
+
+	// No headers configured
+	assert.Panics(func() {
+		RequiredHeaders()
+	})
+}
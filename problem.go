@@ -0,0 +1,144 @@
+package echo
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+type (
+	// Problem represents an RFC 7807 problem details object. It can be returned
+	// from handlers as an error to be rendered as-is by
+	// `Echo#ProblemHTTPErrorHandler()`.
+	Problem struct {
+		Type     string `json:"type,omitempty"`
+		Title    string `json:"title,omitempty"`
+		Status   int    `json:"status,omitempty"`
+		Detail   string `json:"detail,omitempty"`
+		Instance string `json:"instance,omitempty"`
+
+		// Extensions holds extra members which are serialized next to the
+		// standard ones.
+		Extensions Map `json:"-"`
+	}
+)
+
+// NewProblem creates a new Problem instance for the status code.
+func NewProblem(code int, detail string) *Problem {
+	return &Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(code),
+		Status: code,
+		Detail: detail,
+	}
+}
+
+// Error makes it compatible with `error` interface.
+func (p *Problem) Error() string {
+	if p.Detail != "" {
+		return p.Title + ": " + p.Detail
+	}
+	return p.Title
+}
+
+// MarshalJSON implements `json.Marshaler`, inlining the extension members.
+func (p *Problem) MarshalJSON() ([]byte, error) {
+	type problem Problem
+	b, err := json.Marshal((*problem)(p))
+	if err != nil || len(p.Extensions) == 0 {
+		return b, err
+	}
+	m := Map{}
+	for k, v := range p.Extensions {
+		m[k] = v
+	}
+	if err = json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	return json.Marshal(m)
+}
+
+// ProblemHTTPErrorHandler is an HTTP error handler which sends RFC 7807 problem
+// details with `application/problem+json` content type to clients accepting
+// it, and falls back to `Echo#DefaultHTTPErrorHandler()` otherwise. The code
+// and message of an `HTTPError` become the status, title and detail of the
+// problem, with the members of a map message added as extension members.
+// Other errors become a generic 500 problem.
+//
+// Example:
+//
+//	e.HTTPErrorHandler = e.ProblemHTTPErrorHandler
+func (e *Echo) ProblemHTTPErrorHandler(err error, c Context) {
+	if !acceptsProblem(c.Request()) {
+		e.DefaultHTTPErrorHandler(err, c)
+		return
+	}
+	p := e.problem(err)
+	if p.Instance == "" {
+		p.Instance = c.Request().URL.Path
+	}
+
+	// Send response
+	if !c.Response().Committed {
+		if c.Request().Method == http.MethodHead {
+			err = c.NoContent(p.Status)
+		} else {
+			var b []byte
+			if b, err = json.Marshal(p); err == nil {
+				err = c.Blob(p.Status, MIMEApplicationProblemJSON, b)
+			}
+		}
+		if err != nil {
+			e.Logger.Error(err)
+		}
+	}
+}
+
+// problem converts err to a new Problem instance.
+func (e *Echo) problem(err error) *Problem {
+	if p, ok := err.(*Problem); ok {
+		cp := *p
+		return &cp
+	}
+	he, ok := err.(*HTTPError)
+	if ok {
+		if herr, ok := he.Internal.(*HTTPError); ok {
+			he = herr
+		}
+	} else if he = e.mappedHTTPError(err); he == nil {
+		p := NewProblem(http.StatusInternalServerError, "")
+		if e.Debug {
+			p.Detail = err.Error()
+		}
+		return p
+	}
+
+	p := NewProblem(he.Code, "")
+	switch m := he.Message.(type) {
+	case string:
+		if m != p.Title {
+			p.Detail = m
+		}
+	case Map:
+		p.Extensions = m
+	case map[string]interface{}:
+		p.Extensions = m
+	case nil:
+	default:
+		p.Extensions = Map{"message": m}
+	}
+	if e.Debug {
+		p.Detail = err.Error()
+	}
+	return p
+}
+
+// acceptsProblem reports whether the client accepts problem details, which is
+// assumed for JSON clients and clients not stating any preference.
+func acceptsProblem(r *http.Request) bool {
+	accept := r.Header.Get(HeaderAccept)
+	return accept == "" ||
+		strings.Contains(accept, MIMEApplicationProblemJSON) ||
+		strings.Contains(accept, MIMEApplicationJSON) ||
+		strings.Contains(accept, "*/*")
+}
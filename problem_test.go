@@ -0,0 +1,84 @@
+package echo
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProblemHTTPErrorHandler(t *testing.T) {
+	e := New()
+	e.HTTPErrorHandler = e.ProblemHTTPErrorHandler
+	e.GET("/http", func(c Context) error {
+		return NewHTTPError(http.StatusBadRequest, "invalid id")
+	})
+	e.GET("/map", func(c Context) error {
+		return NewHTTPError(http.StatusConflict, Map{"code": 12})
+	})
+	e.GET("/problem", func(c Context) error {
+		p := NewProblem(http.StatusForbidden, "insufficient balance")
+		p.Type = "https://example.com/probs/out-of-credit"
+		p.Extensions = Map{"balance": 30}
+		return p
+	})
+	e.GET("/error", func(c Context) error {
+		return errors.New("boom")
+	})
+	problem := func(path, accept string) (*httptest.ResponseRecorder, map[string]interface{}) {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set(HeaderAccept, accept)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		m := map[string]interface{}{}
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &m))
+		return rec, m
+	}
+
+	// HTTPError
+	rec, m := problem("/http", MIMEApplicationProblemJSON)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, MIMEApplicationProblemJSON, rec.Header().Get(HeaderContentType))
+	assert.Equal(t, map[string]interface{}{
+		"type":     "about:blank",
+		"title":    "Bad Request",
+		"status":   float64(http.StatusBadRequest),
+		"detail":   "invalid id",
+		"instance": "/http",
+	}, m)
+
+	// HTTPError with map message
+	rec, m = problem("/map", MIMEApplicationJSON)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Equal(t, MIMEApplicationProblemJSON, rec.Header().Get(HeaderContentType))
+	assert.Equal(t, "Conflict", m["title"])
+	assert.Equal(t, float64(12), m["code"])
+
+	// Problem
+	rec, m = problem("/problem", "")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Equal(t, map[string]interface{}{
+		"type":     "https://example.com/probs/out-of-credit",
+		"title":    "Forbidden",
+		"status":   float64(http.StatusForbidden),
+		"detail":   "insufficient balance",
+		"instance": "/problem",
+		"balance":  float64(30),
+	}, m)
+
+	// Non-HTTP error
+	rec, m = problem("/error", "*/*")
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Equal(t, MIMEApplicationProblemJSON, rec.Header().Get(HeaderContentType))
+	assert.Equal(t, "Internal Server Error", m["title"])
+	assert.NotContains(t, m, "detail")
+
+	// Not accepted
+	rec, m = problem("/http", MIMEApplicationXML)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, MIMEApplicationJSONCharsetUTF8, rec.Header().Get(HeaderContentType))
+	assert.Equal(t, map[string]interface{}{"message": "invalid id"}, m)
+}
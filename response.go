@@ -4,6 +4,8 @@ import (
 	"bufio"
 	"net"
 	"net/http"
+	"sync"
+	"time"
 )
 
 type (
@@ -15,9 +17,23 @@ type (
 		beforeFuncs []func()
 		afterFuncs  []func()
 		Writer      http.ResponseWriter
-		Status      int
-		Size        int64
-		Committed   bool
+		// Status is the status code sent, or to be sent, to the client. It
+		// defaults to 200 if `Write` is called before `WriteHeader`.
+		Status int
+		// Size is the number of bytes of the response body written so far.
+		Size      int64
+		Committed bool
+	}
+
+	// intervalFlushWriter flushes written data at most interval after it was
+	// written, see `Context#FlushEvery()`.
+	intervalFlushWriter struct {
+		http.ResponseWriter
+		flusher  http.Flusher
+		interval time.Duration
+		mutex    sync.Mutex
+		timer    *time.Timer
+		stopped  bool
 	}
 )
 
@@ -102,3 +118,49 @@ func (r *Response) reset(w http.ResponseWriter) {
 	r.Status = http.StatusOK
 	r.Committed = false
 }
+
+func (w *intervalFlushWriter) Write(b []byte) (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	n, err := w.ResponseWriter.Write(b)
+	if w.stopped {
+		return n, err
+	}
+	if w.interval <= 0 {
+		w.flusher.Flush()
+	} else if w.timer == nil {
+		w.timer = time.AfterFunc(w.interval, w.flushPending)
+	}
+	return n, err
+}
+
+func (w *intervalFlushWriter) flushPending() {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if !w.stopped {
+		w.flusher.Flush()
+	}
+	w.timer = nil
+}
+
+// Flush implements the http.Flusher interface.
+func (w *intervalFlushWriter) Flush() {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.flusher.Flush()
+}
+
+// stop stops flushing on the interval, flushing pending data.
+func (w *intervalFlushWriter) stop() {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.stopped {
+		return
+	}
+	w.stopped = true
+	if w.timer != nil {
+		w.timer.Stop()
+		w.timer = nil
+	}
+	w.flusher.Flush()
+}
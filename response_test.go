@@ -41,3 +41,35 @@ func TestResponse_Write_UsesSetResponseCode(t *testing.T) {
 	res.Write([]byte("test"))
 	assert.Equal(t, http.StatusBadRequest, rec.Code)
 }
+
+func TestResponse_StatusAndSize(t *testing.T) {
+	e := New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	// Implicit status
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.Response().Write([]byte("test"))
+	assert.Equal(t, http.StatusOK, c.Response().Status)
+	assert.Equal(t, int64(4), c.Response().Size)
+	assert.True(t, c.Response().Committed)
+
+	// Explicit status
+	rec = httptest.NewRecorder()
+	c = e.NewContext(req, rec)
+	c.Response().WriteHeader(http.StatusCreated)
+	assert.Equal(t, http.StatusCreated, c.Response().Status)
+	assert.Equal(t, int64(0), c.Response().Size)
+	c.Response().Write([]byte("test"))
+	assert.Equal(t, http.StatusCreated, c.Response().Status)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	// Multiple writes
+	rec = httptest.NewRecorder()
+	c = e.NewContext(req, rec)
+	c.Response().Write([]byte("hello"))
+	c.Response().Write([]byte(", "))
+	c.Response().Write([]byte("world"))
+	assert.Equal(t, int64(12), c.Response().Size)
+	assert.Equal(t, "hello, world", rec.Body.String())
+}
@@ -1,6 +1,11 @@
 package echo
 
-import "net/http"
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
 
 type (
 	// Router is the registry of all registered routes for an `Echo` instance for
@@ -9,6 +14,12 @@ type (
 		tree   *node
 		routes map[string]*Route
 		echo   *Echo
+		// folded holds the routes with lower case static parts if
+		// `Echo#CaseInsensitivePaths` is set.
+		folded *Router
+		// params holds the param names and constraints of the routes by their
+		// path with param names removed, see `Router#addConstraints()`.
+		params map[string]*routeParams
 	}
 	node struct {
 		kind          kind
@@ -20,8 +31,18 @@ type (
 		pnames        []string
 		methodHandler *methodHandler
 	}
-	kind uint8
-	children []*node
+	kind            uint8
+	children        []*node
+	routeConstraint struct {
+		pname string
+		spec  string
+		match func(string) bool
+	}
+	routeParams struct {
+		path        string
+		pnames      string
+		constraints map[string]string // By method
+	}
 	methodHandler struct {
 		connect  HandlerFunc
 		delete   HandlerFunc
@@ -34,9 +55,22 @@ type (
 		put      HandlerFunc
 		trace    HandlerFunc
 		report   HandlerFunc
+		// allow and allowOptions are the values of the Allow header for
+		// requests with other methods, the latter also listing OPTIONS.
+		allow        string
+		allowOptions string
 	}
 )
 
+// optionsHandler answers OPTIONS requests for routes without an OPTIONS
+// handler.
+var optionsHandler = func(c Context) error {
+	if allow := c.matched().allow; allow != "" {
+		c.Response().Header().Set(HeaderAllow, allow)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
 const (
 	skind kind = iota
 	pkind
@@ -50,6 +84,7 @@ func NewRouter(e *Echo) *Router {
 			methodHandler: new(methodHandler),
 		},
 		routes: map[string]*Route{},
+		params: map[string]*routeParams{},
 		echo:   e,
 	}
 }
@@ -63,8 +98,17 @@ func (r *Router) Add(method, path string, h HandlerFunc) {
 	if path[0] != '/' {
 		path = "/" + path
 	}
+	if r.echo.CaseInsensitivePaths {
+		if r.folded == nil {
+			r.folded = NewRouter(r.echo)
+		}
+		r.folded.add(method, foldPath(path, true), path, foldedHandler(path, h, r.echo.RedirectCaseInsensitivePaths))
+	}
+	r.add(method, path, path, h)
+}
+
+func (r *Router) add(method, path, ppath string, h HandlerFunc) {
 	pnames := []string{} // Param names
-	ppath := path        // Pristine path
 
 	for i, l := 0, len(path); i < l; i++ {
 		if path[i] == ':' {
@@ -93,6 +137,192 @@ func (r *Router) Add(method, path string, h HandlerFunc) {
 	r.insert(method, path, h, skind, ppath, pnames)
 }
 
+// foldPath lower cases the ASCII letters of path, except in param names if
+// route is set. It keeps the length of path.
+func foldPath(path string, route bool) string {
+	b := []byte(path)
+	for i := 0; i < len(b); i++ {
+		if route && b[i] == ':' {
+			for ; i < len(b) && b[i] != '/'; i++ {
+			}
+			continue
+		}
+		if 'A' <= b[i] && b[i] <= 'Z' {
+			b[i] += 'a' - 'A'
+		}
+	}
+	return string(b)
+}
+
+// unfoldParams sets the param values found in the folded path to the same
+// parts of the original path.
+func unfoldParams(ppath, path string, pvalues []string) {
+	n := 0
+	for i, j := 0, 0; i < len(ppath) && j <= len(path) && n < len(pvalues); {
+		switch ppath[i] {
+		case ':':
+			k := j
+			for ; k < len(path) && path[k] != '/'; k++ {
+			}
+			pvalues[n] = path[j:k]
+			n++
+			for ; i < len(ppath) && ppath[i] != '/'; i++ {
+			}
+			j = k
+		case '*':
+			pvalues[n] = path[j:]
+			return
+		default:
+			i++
+			j++
+		}
+	}
+}
+
+// foldedHandler returns the handler of a route found case insensitively, which
+// redirects requests to the path with the case of the route if redirect is set.
+func foldedHandler(ppath string, h HandlerFunc, redirect bool) HandlerFunc {
+	if !redirect {
+		return h
+	}
+	return func(c Context) error {
+		if path := unfoldPath(ppath, c.ParamValues()); path != getPath(c.Request()) {
+			return redirectPermanently(c, path)
+		}
+		return h(c)
+	}
+}
+
+// unfoldPath returns ppath with its params replaced by pvalues.
+func unfoldPath(ppath string, pvalues []string) string {
+	b := new(strings.Builder)
+	n := 0
+	for i := 0; i < len(ppath); i++ {
+		switch ppath[i] {
+		case ':', '*':
+			if n < len(pvalues) {
+				b.WriteString(pvalues[n])
+				n++
+			}
+			for ; i+1 < len(ppath) && ppath[i+1] != '/'; i++ {
+			}
+		default:
+			b.WriteByte(ppath[i])
+		}
+	}
+	return b.String()
+}
+
+// parseRouteConstraints strips constraints like `:id<int>` or
+// `:name<regex([a-z]+)>` from path and returns them. Supported constraints are
+// int, uint, alpha, alnum and regex(...). It panics on unknown constraints and
+// invalid regular expressions.
+func parseRouteConstraints(path string) (string, []routeConstraint) {
+	var constraints []routeConstraint
+	for i := 0; i < len(path); i++ {
+		if path[i] != ':' {
+			continue
+		}
+		j := i + 1
+		for ; j < len(path) && path[j] != '/' && path[j] != '<'; j++ {
+		}
+		if j == len(path) || path[j] != '<' {
+			i = j
+			continue
+		}
+		end := ">"
+		if strings.HasPrefix(path[j+1:], "regex(") {
+			end = ")>"
+		}
+		k := strings.Index(path[j+1:], end)
+		if k == -1 {
+			panic("echo: unterminated route constraint in " + path)
+		}
+		spec := path[j+1 : j+1+k+len(end)-1]
+		constraints = append(constraints, routeConstraint{
+			pname: path[i+1 : j],
+			spec:  spec,
+			match: routeConstraintMatcher(spec),
+		})
+		path = path[:j] + path[j+1+k+len(end):]
+		i = j
+	}
+	return path, constraints
+}
+
+// addConstraints records the param names and constraints of the route for
+// method and path, the latter stripped of its constraints. Routes differing
+// only in their param names share a node, so it panics if either of them is
+// constrained, as the constraints would check the wrong params. It also panics
+// if a route is registered again with other constraints.
+func (r *Router) addConstraints(method, path string, constraints []routeConstraint) {
+	shape := new(strings.Builder)
+	var pnames []string
+	for i := 0; i < len(path); i++ {
+		shape.WriteByte(path[i])
+		if path[i] == ':' {
+			j := i + 1
+			for ; j < len(path) && path[j] != '/'; j++ {
+			}
+			pnames = append(pnames, path[i+1:j])
+			i = j - 1
+		}
+	}
+	specs := make([]string, len(constraints))
+	for i, rc := range constraints {
+		specs[i] = rc.pname + "<" + rc.spec + ">"
+	}
+	spec := strings.Join(specs, ",")
+
+	p, ok := r.params[shape.String()]
+	if !ok {
+		r.params[shape.String()] = &routeParams{
+			path:        path,
+			pnames:      strings.Join(pnames, ","),
+			constraints: map[string]string{method: spec},
+		}
+		return
+	}
+	constrained := spec != ""
+	for _, s := range p.constraints {
+		constrained = constrained || s != ""
+	}
+	if constrained && p.pnames != strings.Join(pnames, ",") {
+		panic("echo: route " + method + " " + path + " conflicts with the params of constrained route " + p.path)
+	}
+	if s, ok := p.constraints[method]; ok && s != spec {
+		panic("echo: route " + method + " " + path + " conflicts with the constraints of route " + p.path)
+	}
+	p.constraints[method] = spec
+}
+
+func routeConstraintMatcher(spec string) func(string) bool {
+	switch spec {
+	case "int":
+		return func(v string) bool {
+			_, err := strconv.ParseInt(v, 10, 64)
+			return err == nil
+		}
+	case "uint":
+		return func(v string) bool {
+			_, err := strconv.ParseUint(v, 10, 64)
+			return err == nil
+		}
+	case "alpha":
+		return regexp.MustCompile(`^[a-zA-Z]+$`).MatchString
+	case "alnum":
+		return regexp.MustCompile(`^[a-zA-Z0-9]+$`).MatchString
+	}
+	if strings.HasPrefix(spec, "regex(") && strings.HasSuffix(spec, ")") {
+		re, err := regexp.Compile("^(?:" + spec[len("regex("):len(spec)-1] + ")$")
+		if err != nil {
+			panic("echo: invalid route constraint " + spec + ": " + err.Error())
+		}
+		return re.MatchString
+	}
+	panic("echo: unknown route constraint " + spec)
+}
+
 func (r *Router) insert(method, path string, h HandlerFunc, t kind, ppath string, pnames []string) {
 	// Adjust max param
 	l := len(pnames)
@@ -251,6 +481,24 @@ func (n *node) addHandler(method string, h HandlerFunc) {
 	case REPORT:
 		n.methodHandler.report = h
 	}
+	n.updateAllow()
+}
+
+// updateAllow sets the Allow header values of the node to the methods it has
+// handlers for.
+func (n *node) updateAllow() {
+	allowed := make([]string, 0, len(methods))
+	for _, m := range methods {
+		if h := n.findHandler(m); h != nil {
+			allowed = append(allowed, m)
+		}
+	}
+	mh := n.methodHandler
+	mh.allow = strings.Join(allowed, ", ")
+	mh.allowOptions = mh.allow
+	if mh.allow != "" && mh.options == nil {
+		mh.allowOptions += ", " + http.MethodOptions
+	}
 }
 
 func (n *node) findHandler(method string) HandlerFunc {
@@ -282,13 +530,23 @@ func (n *node) findHandler(method string) HandlerFunc {
 	}
 }
 
-func (n *node) checkMethodNotAllowed() HandlerFunc {
-	for _, m := range methods {
-		if h := n.findHandler(m); h != nil {
-			return MethodNotAllowedHandler
-		}
+// checkMethodNotAllowed returns the handler for a request with a method the
+// node has no handler for, and the value of its Allow header. If the node has
+// handlers for other methods, it responds with "405 - Method Not Allowed"
+// listing them in the Allow header, or answers OPTIONS requests unless
+// `Echo#DisableAutoOptions` is set.
+func (n *node) checkMethodNotAllowed(method string, autoOptions bool) (HandlerFunc, string) {
+	mh := n.methodHandler
+	if mh.allow == "" {
+		return NotFoundHandler, ""
+	}
+	if !autoOptions {
+		return MethodNotAllowedHandler, mh.allow
 	}
-	return NotFoundHandler
+	if method == http.MethodOptions {
+		return optionsHandler, mh.allowOptions
+	}
+	return MethodNotAllowedHandler, mh.allowOptions
 }
 
 // Find lookup a handler registered for method and path. It also parses URL for path
@@ -300,6 +558,27 @@ func (n *node) checkMethodNotAllowed() HandlerFunc {
 // - Reset it `Context#Reset()`
 // - Return it `Echo#ReleaseContext()`.
 func (r *Router) Find(method, path string, c Context) {
+	allow, hit := r.find(method, path, c)
+	// Routes added before `Echo#CaseInsensitivePaths` was set are only in the
+	// tree, so the folded routes are looked up only if it has no handler.
+	if r.folded != nil && !hit {
+		ctx := c.(*context)
+		if fallow, fhit := r.folded.find(method, foldPath(path, false), c); fhit || fallow != "" {
+			allow = fallow
+			unfoldParams(ctx.path, path, ctx.pvalues[:len(ctx.pnames)])
+		} else {
+			allow, _ = r.find(method, path, c)
+		}
+	}
+	if allow != "" {
+		c.matched().allow = allow
+	}
+}
+
+// find looks up the handler for method and path like `Router#Find()`. It
+// returns the value of the Allow header if the path matched a route without a
+// handler for method, and whether it has one.
+func (r *Router) find(method, path string, c Context) (allow string, hit bool) {
 	ctx := c.(*context)
 	ctx.path = path
 	cn := r.tree // Current node as root
@@ -342,7 +621,7 @@ func (r *Router) Find(method, path string, c Context) {
 			search = search[l:]
 		} else {
 			if nn == nil { // Issue #1348
-				return // Not found
+				return "", false // Not found
 			}
 			cn = nn
 			search = ns
@@ -410,7 +689,7 @@ func (r *Router) Find(method, path string, c Context) {
 					goto Any
 				}
 			}
-			return // Not found
+			return "", false // Not found
 		}
 		pvalues[len(cn.pnames)-1] = search
 		break
@@ -422,22 +701,24 @@ func (r *Router) Find(method, path string, c Context) {
 
 	// NOTE: Slow zone...
 	if ctx.handler == nil {
-		ctx.handler = cn.checkMethodNotAllowed()
+		ctx.handler, allow = cn.checkMethodNotAllowed(method, !r.echo.DisableAutoOptions)
 
 		// Dig further for any, might have an empty value for *, e.g.
 		// serving a directory. Issue #207.
-		if cn = cn.findChildByKind(akind); cn == nil {
-			return
-		}
-		if h := cn.findHandler(method); h != nil {
-			ctx.handler = h
-		} else {
-			ctx.handler = cn.checkMethodNotAllowed()
+		if an := cn.findChildByKind(akind); an != nil {
+			cn = an
+			if h := cn.findHandler(method); h != nil {
+				ctx.handler, allow = h, ""
+				hit = true
+			} else {
+				ctx.handler, allow = cn.checkMethodNotAllowed(method, !r.echo.DisableAutoOptions)
+			}
+			ctx.path = cn.ppath
+			ctx.pnames = cn.pnames
+			pvalues[len(cn.pnames)-1] = ""
 		}
-		ctx.path = cn.ppath
-		ctx.pnames = cn.pnames
-		pvalues[len(cn.pnames)-1] = ""
+		return
 	}
 
-	return
+	return "", true
 }
@@ -3,6 +3,7 @@ package echo
 import (
 	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 
@@ -11,493 +12,493 @@ import (
 
 var (
 	staticRoutes = []*Route{
-		{"GET", "/", ""},
-		{"GET", "/cmd.html", ""},
-		{"GET", "/code.html", ""},
-		{"GET", "/contrib.html", ""},
-		{"GET", "/contribute.html", ""},
-		{"GET", "/debugging_with_gdb.html", ""},
-		{"GET", "/docs.html", ""},
-		{"GET", "/effective_go.html", ""},
-		{"GET", "/files.log", ""},
-		{"GET", "/gccgo_contribute.html", ""},
-		{"GET", "/gccgo_install.html", ""},
-		{"GET", "/go-logo-black.png", ""},
-		{"GET", "/go-logo-blue.png", ""},
-		{"GET", "/go-logo-white.png", ""},
-		{"GET", "/go1.1.html", ""},
-		{"GET", "/go1.2.html", ""},
-		{"GET", "/go1.html", ""},
-		{"GET", "/go1compat.html", ""},
-		{"GET", "/go_faq.html", ""},
-		{"GET", "/go_mem.html", ""},
-		{"GET", "/go_spec.html", ""},
-		{"GET", "/help.html", ""},
-		{"GET", "/ie.css", ""},
-		{"GET", "/install-source.html", ""},
-		{"GET", "/install.html", ""},
-		{"GET", "/logo-153x55.png", ""},
-		{"GET", "/Makefile", ""},
-		{"GET", "/root.html", ""},
-		{"GET", "/share.png", ""},
-		{"GET", "/sieve.gif", ""},
-		{"GET", "/tos.html", ""},
-		{"GET", "/articles/", ""},
-		{"GET", "/articles/go_command.html", ""},
-		{"GET", "/articles/index.html", ""},
-		{"GET", "/articles/wiki/", ""},
-		{"GET", "/articles/wiki/edit.html", ""},
-		{"GET", "/articles/wiki/final-noclosure.go", ""},
-		{"GET", "/articles/wiki/final-noerror.go", ""},
-		{"GET", "/articles/wiki/final-parsetemplate.go", ""},
-		{"GET", "/articles/wiki/final-template.go", ""},
-		{"GET", "/articles/wiki/final.go", ""},
-		{"GET", "/articles/wiki/get.go", ""},
-		{"GET", "/articles/wiki/http-sample.go", ""},
-		{"GET", "/articles/wiki/index.html", ""},
-		{"GET", "/articles/wiki/Makefile", ""},
-		{"GET", "/articles/wiki/notemplate.go", ""},
-		{"GET", "/articles/wiki/part1-noerror.go", ""},
-		{"GET", "/articles/wiki/part1.go", ""},
-		{"GET", "/articles/wiki/part2.go", ""},
-		{"GET", "/articles/wiki/part3-errorhandling.go", ""},
-		{"GET", "/articles/wiki/part3.go", ""},
-		{"GET", "/articles/wiki/test.bash", ""},
-		{"GET", "/articles/wiki/test_edit.good", ""},
-		{"GET", "/articles/wiki/test_Test.txt.good", ""},
-		{"GET", "/articles/wiki/test_view.good", ""},
-		{"GET", "/articles/wiki/view.html", ""},
-		{"GET", "/codewalk/", ""},
-		{"GET", "/codewalk/codewalk.css", ""},
-		{"GET", "/codewalk/codewalk.js", ""},
-		{"GET", "/codewalk/codewalk.xml", ""},
-		{"GET", "/codewalk/functions.xml", ""},
-		{"GET", "/codewalk/markov.go", ""},
-		{"GET", "/codewalk/markov.xml", ""},
-		{"GET", "/codewalk/pig.go", ""},
-		{"GET", "/codewalk/popout.png", ""},
-		{"GET", "/codewalk/run", ""},
-		{"GET", "/codewalk/sharemem.xml", ""},
-		{"GET", "/codewalk/urlpoll.go", ""},
-		{"GET", "/devel/", ""},
-		{"GET", "/devel/release.html", ""},
-		{"GET", "/devel/weekly.html", ""},
-		{"GET", "/gopher/", ""},
-		{"GET", "/gopher/appenginegopher.jpg", ""},
-		{"GET", "/gopher/appenginegophercolor.jpg", ""},
-		{"GET", "/gopher/appenginelogo.gif", ""},
-		{"GET", "/gopher/bumper.png", ""},
-		{"GET", "/gopher/bumper192x108.png", ""},
-		{"GET", "/gopher/bumper320x180.png", ""},
-		{"GET", "/gopher/bumper480x270.png", ""},
-		{"GET", "/gopher/bumper640x360.png", ""},
-		{"GET", "/gopher/doc.png", ""},
-		{"GET", "/gopher/frontpage.png", ""},
-		{"GET", "/gopher/gopherbw.png", ""},
-		{"GET", "/gopher/gophercolor.png", ""},
-		{"GET", "/gopher/gophercolor16x16.png", ""},
-		{"GET", "/gopher/help.png", ""},
-		{"GET", "/gopher/pkg.png", ""},
-		{"GET", "/gopher/project.png", ""},
-		{"GET", "/gopher/ref.png", ""},
-		{"GET", "/gopher/run.png", ""},
-		{"GET", "/gopher/talks.png", ""},
-		{"GET", "/gopher/pencil/", ""},
-		{"GET", "/gopher/pencil/gopherhat.jpg", ""},
-		{"GET", "/gopher/pencil/gopherhelmet.jpg", ""},
-		{"GET", "/gopher/pencil/gophermega.jpg", ""},
-		{"GET", "/gopher/pencil/gopherrunning.jpg", ""},
-		{"GET", "/gopher/pencil/gopherswim.jpg", ""},
-		{"GET", "/gopher/pencil/gopherswrench.jpg", ""},
-		{"GET", "/play/", ""},
-		{"GET", "/play/fib.go", ""},
-		{"GET", "/play/hello.go", ""},
-		{"GET", "/play/life.go", ""},
-		{"GET", "/play/peano.go", ""},
-		{"GET", "/play/pi.go", ""},
-		{"GET", "/play/sieve.go", ""},
-		{"GET", "/play/solitaire.go", ""},
-		{"GET", "/play/tree.go", ""},
-		{"GET", "/progs/", ""},
-		{"GET", "/progs/cgo1.go", ""},
-		{"GET", "/progs/cgo2.go", ""},
-		{"GET", "/progs/cgo3.go", ""},
-		{"GET", "/progs/cgo4.go", ""},
-		{"GET", "/progs/defer.go", ""},
-		{"GET", "/progs/defer.out", ""},
-		{"GET", "/progs/defer2.go", ""},
-		{"GET", "/progs/defer2.out", ""},
-		{"GET", "/progs/eff_bytesize.go", ""},
-		{"GET", "/progs/eff_bytesize.out", ""},
-		{"GET", "/progs/eff_qr.go", ""},
-		{"GET", "/progs/eff_sequence.go", ""},
-		{"GET", "/progs/eff_sequence.out", ""},
-		{"GET", "/progs/eff_unused1.go", ""},
-		{"GET", "/progs/eff_unused2.go", ""},
-		{"GET", "/progs/error.go", ""},
-		{"GET", "/progs/error2.go", ""},
-		{"GET", "/progs/error3.go", ""},
-		{"GET", "/progs/error4.go", ""},
-		{"GET", "/progs/go1.go", ""},
-		{"GET", "/progs/gobs1.go", ""},
-		{"GET", "/progs/gobs2.go", ""},
-		{"GET", "/progs/image_draw.go", ""},
-		{"GET", "/progs/image_package1.go", ""},
-		{"GET", "/progs/image_package1.out", ""},
-		{"GET", "/progs/image_package2.go", ""},
-		{"GET", "/progs/image_package2.out", ""},
-		{"GET", "/progs/image_package3.go", ""},
-		{"GET", "/progs/image_package3.out", ""},
-		{"GET", "/progs/image_package4.go", ""},
-		{"GET", "/progs/image_package4.out", ""},
-		{"GET", "/progs/image_package5.go", ""},
-		{"GET", "/progs/image_package5.out", ""},
-		{"GET", "/progs/image_package6.go", ""},
-		{"GET", "/progs/image_package6.out", ""},
-		{"GET", "/progs/interface.go", ""},
-		{"GET", "/progs/interface2.go", ""},
-		{"GET", "/progs/interface2.out", ""},
-		{"GET", "/progs/json1.go", ""},
-		{"GET", "/progs/json2.go", ""},
-		{"GET", "/progs/json2.out", ""},
-		{"GET", "/progs/json3.go", ""},
-		{"GET", "/progs/json4.go", ""},
-		{"GET", "/progs/json5.go", ""},
-		{"GET", "/progs/run", ""},
-		{"GET", "/progs/slices.go", ""},
-		{"GET", "/progs/timeout1.go", ""},
-		{"GET", "/progs/timeout2.go", ""},
-		{"GET", "/progs/update.bash", ""},
+		{Method: "GET", Path: "/", Name: ""},
+		{Method: "GET", Path: "/cmd.html", Name: ""},
+		{Method: "GET", Path: "/code.html", Name: ""},
+		{Method: "GET", Path: "/contrib.html", Name: ""},
+		{Method: "GET", Path: "/contribute.html", Name: ""},
+		{Method: "GET", Path: "/debugging_with_gdb.html", Name: ""},
+		{Method: "GET", Path: "/docs.html", Name: ""},
+		{Method: "GET", Path: "/effective_go.html", Name: ""},
+		{Method: "GET", Path: "/files.log", Name: ""},
+		{Method: "GET", Path: "/gccgo_contribute.html", Name: ""},
+		{Method: "GET", Path: "/gccgo_install.html", Name: ""},
+		{Method: "GET", Path: "/go-logo-black.png", Name: ""},
+		{Method: "GET", Path: "/go-logo-blue.png", Name: ""},
+		{Method: "GET", Path: "/go-logo-white.png", Name: ""},
+		{Method: "GET", Path: "/go1.1.html", Name: ""},
+		{Method: "GET", Path: "/go1.2.html", Name: ""},
+		{Method: "GET", Path: "/go1.html", Name: ""},
+		{Method: "GET", Path: "/go1compat.html", Name: ""},
+		{Method: "GET", Path: "/go_faq.html", Name: ""},
+		{Method: "GET", Path: "/go_mem.html", Name: ""},
+		{Method: "GET", Path: "/go_spec.html", Name: ""},
+		{Method: "GET", Path: "/help.html", Name: ""},
+		{Method: "GET", Path: "/ie.css", Name: ""},
+		{Method: "GET", Path: "/install-source.html", Name: ""},
+		{Method: "GET", Path: "/install.html", Name: ""},
+		{Method: "GET", Path: "/logo-153x55.png", Name: ""},
+		{Method: "GET", Path: "/Makefile", Name: ""},
+		{Method: "GET", Path: "/root.html", Name: ""},
+		{Method: "GET", Path: "/share.png", Name: ""},
+		{Method: "GET", Path: "/sieve.gif", Name: ""},
+		{Method: "GET", Path: "/tos.html", Name: ""},
+		{Method: "GET", Path: "/articles/", Name: ""},
+		{Method: "GET", Path: "/articles/go_command.html", Name: ""},
+		{Method: "GET", Path: "/articles/index.html", Name: ""},
+		{Method: "GET", Path: "/articles/wiki/", Name: ""},
+		{Method: "GET", Path: "/articles/wiki/edit.html", Name: ""},
+		{Method: "GET", Path: "/articles/wiki/final-noclosure.go", Name: ""},
+		{Method: "GET", Path: "/articles/wiki/final-noerror.go", Name: ""},
+		{Method: "GET", Path: "/articles/wiki/final-parsetemplate.go", Name: ""},
+		{Method: "GET", Path: "/articles/wiki/final-template.go", Name: ""},
+		{Method: "GET", Path: "/articles/wiki/final.go", Name: ""},
+		{Method: "GET", Path: "/articles/wiki/get.go", Name: ""},
+		{Method: "GET", Path: "/articles/wiki/http-sample.go", Name: ""},
+		{Method: "GET", Path: "/articles/wiki/index.html", Name: ""},
+		{Method: "GET", Path: "/articles/wiki/Makefile", Name: ""},
+		{Method: "GET", Path: "/articles/wiki/notemplate.go", Name: ""},
+		{Method: "GET", Path: "/articles/wiki/part1-noerror.go", Name: ""},
+		{Method: "GET", Path: "/articles/wiki/part1.go", Name: ""},
+		{Method: "GET", Path: "/articles/wiki/part2.go", Name: ""},
+		{Method: "GET", Path: "/articles/wiki/part3-errorhandling.go", Name: ""},
+		{Method: "GET", Path: "/articles/wiki/part3.go", Name: ""},
+		{Method: "GET", Path: "/articles/wiki/test.bash", Name: ""},
+		{Method: "GET", Path: "/articles/wiki/test_edit.good", Name: ""},
+		{Method: "GET", Path: "/articles/wiki/test_Test.txt.good", Name: ""},
+		{Method: "GET", Path: "/articles/wiki/test_view.good", Name: ""},
+		{Method: "GET", Path: "/articles/wiki/view.html", Name: ""},
+		{Method: "GET", Path: "/codewalk/", Name: ""},
+		{Method: "GET", Path: "/codewalk/codewalk.css", Name: ""},
+		{Method: "GET", Path: "/codewalk/codewalk.js", Name: ""},
+		{Method: "GET", Path: "/codewalk/codewalk.xml", Name: ""},
+		{Method: "GET", Path: "/codewalk/functions.xml", Name: ""},
+		{Method: "GET", Path: "/codewalk/markov.go", Name: ""},
+		{Method: "GET", Path: "/codewalk/markov.xml", Name: ""},
+		{Method: "GET", Path: "/codewalk/pig.go", Name: ""},
+		{Method: "GET", Path: "/codewalk/popout.png", Name: ""},
+		{Method: "GET", Path: "/codewalk/run", Name: ""},
+		{Method: "GET", Path: "/codewalk/sharemem.xml", Name: ""},
+		{Method: "GET", Path: "/codewalk/urlpoll.go", Name: ""},
+		{Method: "GET", Path: "/devel/", Name: ""},
+		{Method: "GET", Path: "/devel/release.html", Name: ""},
+		{Method: "GET", Path: "/devel/weekly.html", Name: ""},
+		{Method: "GET", Path: "/gopher/", Name: ""},
+		{Method: "GET", Path: "/gopher/appenginegopher.jpg", Name: ""},
+		{Method: "GET", Path: "/gopher/appenginegophercolor.jpg", Name: ""},
+		{Method: "GET", Path: "/gopher/appenginelogo.gif", Name: ""},
+		{Method: "GET", Path: "/gopher/bumper.png", Name: ""},
+		{Method: "GET", Path: "/gopher/bumper192x108.png", Name: ""},
+		{Method: "GET", Path: "/gopher/bumper320x180.png", Name: ""},
+		{Method: "GET", Path: "/gopher/bumper480x270.png", Name: ""},
+		{Method: "GET", Path: "/gopher/bumper640x360.png", Name: ""},
+		{Method: "GET", Path: "/gopher/doc.png", Name: ""},
+		{Method: "GET", Path: "/gopher/frontpage.png", Name: ""},
+		{Method: "GET", Path: "/gopher/gopherbw.png", Name: ""},
+		{Method: "GET", Path: "/gopher/gophercolor.png", Name: ""},
+		{Method: "GET", Path: "/gopher/gophercolor16x16.png", Name: ""},
+		{Method: "GET", Path: "/gopher/help.png", Name: ""},
+		{Method: "GET", Path: "/gopher/pkg.png", Name: ""},
+		{Method: "GET", Path: "/gopher/project.png", Name: ""},
+		{Method: "GET", Path: "/gopher/ref.png", Name: ""},
+		{Method: "GET", Path: "/gopher/run.png", Name: ""},
+		{Method: "GET", Path: "/gopher/talks.png", Name: ""},
+		{Method: "GET", Path: "/gopher/pencil/", Name: ""},
+		{Method: "GET", Path: "/gopher/pencil/gopherhat.jpg", Name: ""},
+		{Method: "GET", Path: "/gopher/pencil/gopherhelmet.jpg", Name: ""},
+		{Method: "GET", Path: "/gopher/pencil/gophermega.jpg", Name: ""},
+		{Method: "GET", Path: "/gopher/pencil/gopherrunning.jpg", Name: ""},
+		{Method: "GET", Path: "/gopher/pencil/gopherswim.jpg", Name: ""},
+		{Method: "GET", Path: "/gopher/pencil/gopherswrench.jpg", Name: ""},
+		{Method: "GET", Path: "/play/", Name: ""},
+		{Method: "GET", Path: "/play/fib.go", Name: ""},
+		{Method: "GET", Path: "/play/hello.go", Name: ""},
+		{Method: "GET", Path: "/play/life.go", Name: ""},
+		{Method: "GET", Path: "/play/peano.go", Name: ""},
+		{Method: "GET", Path: "/play/pi.go", Name: ""},
+		{Method: "GET", Path: "/play/sieve.go", Name: ""},
+		{Method: "GET", Path: "/play/solitaire.go", Name: ""},
+		{Method: "GET", Path: "/play/tree.go", Name: ""},
+		{Method: "GET", Path: "/progs/", Name: ""},
+		{Method: "GET", Path: "/progs/cgo1.go", Name: ""},
+		{Method: "GET", Path: "/progs/cgo2.go", Name: ""},
+		{Method: "GET", Path: "/progs/cgo3.go", Name: ""},
+		{Method: "GET", Path: "/progs/cgo4.go", Name: ""},
+		{Method: "GET", Path: "/progs/defer.go", Name: ""},
+		{Method: "GET", Path: "/progs/defer.out", Name: ""},
+		{Method: "GET", Path: "/progs/defer2.go", Name: ""},
+		{Method: "GET", Path: "/progs/defer2.out", Name: ""},
+		{Method: "GET", Path: "/progs/eff_bytesize.go", Name: ""},
+		{Method: "GET", Path: "/progs/eff_bytesize.out", Name: ""},
+		{Method: "GET", Path: "/progs/eff_qr.go", Name: ""},
+		{Method: "GET", Path: "/progs/eff_sequence.go", Name: ""},
+		{Method: "GET", Path: "/progs/eff_sequence.out", Name: ""},
+		{Method: "GET", Path: "/progs/eff_unused1.go", Name: ""},
+		{Method: "GET", Path: "/progs/eff_unused2.go", Name: ""},
+		{Method: "GET", Path: "/progs/error.go", Name: ""},
+		{Method: "GET", Path: "/progs/error2.go", Name: ""},
+		{Method: "GET", Path: "/progs/error3.go", Name: ""},
+		{Method: "GET", Path: "/progs/error4.go", Name: ""},
+		{Method: "GET", Path: "/progs/go1.go", Name: ""},
+		{Method: "GET", Path: "/progs/gobs1.go", Name: ""},
+		{Method: "GET", Path: "/progs/gobs2.go", Name: ""},
+		{Method: "GET", Path: "/progs/image_draw.go", Name: ""},
+		{Method: "GET", Path: "/progs/image_package1.go", Name: ""},
+		{Method: "GET", Path: "/progs/image_package1.out", Name: ""},
+		{Method: "GET", Path: "/progs/image_package2.go", Name: ""},
+		{Method: "GET", Path: "/progs/image_package2.out", Name: ""},
+		{Method: "GET", Path: "/progs/image_package3.go", Name: ""},
+		{Method: "GET", Path: "/progs/image_package3.out", Name: ""},
+		{Method: "GET", Path: "/progs/image_package4.go", Name: ""},
+		{Method: "GET", Path: "/progs/image_package4.out", Name: ""},
+		{Method: "GET", Path: "/progs/image_package5.go", Name: ""},
+		{Method: "GET", Path: "/progs/image_package5.out", Name: ""},
+		{Method: "GET", Path: "/progs/image_package6.go", Name: ""},
+		{Method: "GET", Path: "/progs/image_package6.out", Name: ""},
+		{Method: "GET", Path: "/progs/interface.go", Name: ""},
+		{Method: "GET", Path: "/progs/interface2.go", Name: ""},
+		{Method: "GET", Path: "/progs/interface2.out", Name: ""},
+		{Method: "GET", Path: "/progs/json1.go", Name: ""},
+		{Method: "GET", Path: "/progs/json2.go", Name: ""},
+		{Method: "GET", Path: "/progs/json2.out", Name: ""},
+		{Method: "GET", Path: "/progs/json3.go", Name: ""},
+		{Method: "GET", Path: "/progs/json4.go", Name: ""},
+		{Method: "GET", Path: "/progs/json5.go", Name: ""},
+		{Method: "GET", Path: "/progs/run", Name: ""},
+		{Method: "GET", Path: "/progs/slices.go", Name: ""},
+		{Method: "GET", Path: "/progs/timeout1.go", Name: ""},
+		{Method: "GET", Path: "/progs/timeout2.go", Name: ""},
+		{Method: "GET", Path: "/progs/update.bash", Name: ""},
 	}
 
 	gitHubAPI = []*Route{
 		// OAuth Authorizations
-		{"GET", "/authorizations", ""},
-		{"GET", "/authorizations/:id", ""},
-		{"POST", "/authorizations", ""},
-		//{"PUT", "/authorizations/clients/:client_id", ""},
-		//{"PATCH", "/authorizations/:id", ""},
-		{"DELETE", "/authorizations/:id", ""},
-		{"GET", "/applications/:client_id/tokens/:access_token", ""},
-		{"DELETE", "/applications/:client_id/tokens", ""},
-		{"DELETE", "/applications/:client_id/tokens/:access_token", ""},
+		{Method: "GET", Path: "/authorizations", Name: ""},
+		{Method: "GET", Path: "/authorizations/:id", Name: ""},
+		{Method: "POST", Path: "/authorizations", Name: ""},
+		//{Method: "PUT", Path: "/authorizations/clients/:client_id", Name: ""},
+		//{Method: "PATCH", Path: "/authorizations/:id", Name: ""},
+		{Method: "DELETE", Path: "/authorizations/:id", Name: ""},
+		{Method: "GET", Path: "/applications/:client_id/tokens/:access_token", Name: ""},
+		{Method: "DELETE", Path: "/applications/:client_id/tokens", Name: ""},
+		{Method: "DELETE", Path: "/applications/:client_id/tokens/:access_token", Name: ""},
 
 		// Activity
-		{"GET", "/events", ""},
-		{"GET", "/repos/:owner/:repo/events", ""},
-		{"GET", "/networks/:owner/:repo/events", ""},
-		{"GET", "/orgs/:org/events", ""},
-		{"GET", "/users/:user/received_events", ""},
-		{"GET", "/users/:user/received_events/public", ""},
-		{"GET", "/users/:user/events", ""},
-		{"GET", "/users/:user/events/public", ""},
-		{"GET", "/users/:user/events/orgs/:org", ""},
-		{"GET", "/feeds", ""},
-		{"GET", "/notifications", ""},
-		{"GET", "/repos/:owner/:repo/notifications", ""},
-		{"PUT", "/notifications", ""},
-		{"PUT", "/repos/:owner/:repo/notifications", ""},
-		{"GET", "/notifications/threads/:id", ""},
-		//{"PATCH", "/notifications/threads/:id", ""},
-		{"GET", "/notifications/threads/:id/subscription", ""},
-		{"PUT", "/notifications/threads/:id/subscription", ""},
-		{"DELETE", "/notifications/threads/:id/subscription", ""},
-		{"GET", "/repos/:owner/:repo/stargazers", ""},
-		{"GET", "/users/:user/starred", ""},
-		{"GET", "/user/starred", ""},
-		{"GET", "/user/starred/:owner/:repo", ""},
-		{"PUT", "/user/starred/:owner/:repo", ""},
-		{"DELETE", "/user/starred/:owner/:repo", ""},
-		{"GET", "/repos/:owner/:repo/subscribers", ""},
-		{"GET", "/users/:user/subscriptions", ""},
-		{"GET", "/user/subscriptions", ""},
-		{"GET", "/repos/:owner/:repo/subscription", ""},
-		{"PUT", "/repos/:owner/:repo/subscription", ""},
-		{"DELETE", "/repos/:owner/:repo/subscription", ""},
-		{"GET", "/user/subscriptions/:owner/:repo", ""},
-		{"PUT", "/user/subscriptions/:owner/:repo", ""},
-		{"DELETE", "/user/subscriptions/:owner/:repo", ""},
+		{Method: "GET", Path: "/events", Name: ""},
+		{Method: "GET", Path: "/repos/:owner/:repo/events", Name: ""},
+		{Method: "GET", Path: "/networks/:owner/:repo/events", Name: ""},
+		{Method: "GET", Path: "/orgs/:org/events", Name: ""},
+		{Method: "GET", Path: "/users/:user/received_events", Name: ""},
+		{Method: "GET", Path: "/users/:user/received_events/public", Name: ""},
+		{Method: "GET", Path: "/users/:user/events", Name: ""},
+		{Method: "GET", Path: "/users/:user/events/public", Name: ""},
+		{Method: "GET", Path: "/users/:user/events/orgs/:org", Name: ""},
+		{Method: "GET", Path: "/feeds", Name: ""},
+		{Method: "GET", Path: "/notifications", Name: ""},
+		{Method: "GET", Path: "/repos/:owner/:repo/notifications", Name: ""},
+		{Method: "PUT", Path: "/notifications", Name: ""},
+		{Method: "PUT", Path: "/repos/:owner/:repo/notifications", Name: ""},
+		{Method: "GET", Path: "/notifications/threads/:id", Name: ""},
+		//{Method: "PATCH", Path: "/notifications/threads/:id", Name: ""},
+		{Method: "GET", Path: "/notifications/threads/:id/subscription", Name: ""},
+		{Method: "PUT", Path: "/notifications/threads/:id/subscription", Name: ""},
+		{Method: "DELETE", Path: "/notifications/threads/:id/subscription", Name: ""},
+		{Method: "GET", Path: "/repos/:owner/:repo/stargazers", Name: ""},
+		{Method: "GET", Path: "/users/:user/starred", Name: ""},
+		{Method: "GET", Path: "/user/starred", Name: ""},
+		{Method: "GET", Path: "/user/starred/:owner/:repo", Name: ""},
+		{Method: "PUT", Path: "/user/starred/:owner/:repo", Name: ""},
+		{Method: "DELETE", Path: "/user/starred/:owner/:repo", Name: ""},
+		{Method: "GET", Path: "/repos/:owner/:repo/subscribers", Name: ""},
+		{Method: "GET", Path: "/users/:user/subscriptions", Name: ""},
+		{Method: "GET", Path: "/user/subscriptions", Name: ""},
+		{Method: "GET", Path: "/repos/:owner/:repo/subscription", Name: ""},
+		{Method: "PUT", Path: "/repos/:owner/:repo/subscription", Name: ""},
+		{Method: "DELETE", Path: "/repos/:owner/:repo/subscription", Name: ""},
+		{Method: "GET", Path: "/user/subscriptions/:owner/:repo", Name: ""},
+		{Method: "PUT", Path: "/user/subscriptions/:owner/:repo", Name: ""},
+		{Method: "DELETE", Path: "/user/subscriptions/:owner/:repo", Name: ""},
 
 		// Gists
-		{"GET", "/users/:user/gists", ""},
-		{"GET", "/gists", ""},
-		//{"GET", "/gists/public", ""},
-		//{"GET", "/gists/starred", ""},
-		{"GET", "/gists/:id", ""},
-		{"POST", "/gists", ""},
-		//{"PATCH", "/gists/:id", ""},
-		{"PUT", "/gists/:id/star", ""},
-		{"DELETE", "/gists/:id/star", ""},
-		{"GET", "/gists/:id/star", ""},
-		{"POST", "/gists/:id/forks", ""},
-		{"DELETE", "/gists/:id", ""},
+		{Method: "GET", Path: "/users/:user/gists", Name: ""},
+		{Method: "GET", Path: "/gists", Name: ""},
+		//{Method: "GET", Path: "/gists/public", Name: ""},
+		//{Method: "GET", Path: "/gists/starred", Name: ""},
+		{Method: "GET", Path: "/gists/:id", Name: ""},
+		{Method: "POST", Path: "/gists", Name: ""},
+		//{Method: "PATCH", Path: "/gists/:id", Name: ""},
+		{Method: "PUT", Path: "/gists/:id/star", Name: ""},
+		{Method: "DELETE", Path: "/gists/:id/star", Name: ""},
+		{Method: "GET", Path: "/gists/:id/star", Name: ""},
+		{Method: "POST", Path: "/gists/:id/forks", Name: ""},
+		{Method: "DELETE", Path: "/gists/:id", Name: ""},
 
 		// Git Data
-		{"GET", "/repos/:owner/:repo/git/blobs/:sha", ""},
-		{"POST", "/repos/:owner/:repo/git/blobs", ""},
-		{"GET", "/repos/:owner/:repo/git/commits/:sha", ""},
-		{"POST", "/repos/:owner/:repo/git/commits", ""},
-		//{"GET", "/repos/:owner/:repo/git/refs/*ref", ""},
-		{"GET", "/repos/:owner/:repo/git/refs", ""},
-		{"POST", "/repos/:owner/:repo/git/refs", ""},
-		//{"PATCH", "/repos/:owner/:repo/git/refs/*ref", ""},
-		//{"DELETE", "/repos/:owner/:repo/git/refs/*ref", ""},
-		{"GET", "/repos/:owner/:repo/git/tags/:sha", ""},
-		{"POST", "/repos/:owner/:repo/git/tags", ""},
-		{"GET", "/repos/:owner/:repo/git/trees/:sha", ""},
-		{"POST", "/repos/:owner/:repo/git/trees", ""},
+		{Method: "GET", Path: "/repos/:owner/:repo/git/blobs/:sha", Name: ""},
+		{Method: "POST", Path: "/repos/:owner/:repo/git/blobs", Name: ""},
+		{Method: "GET", Path: "/repos/:owner/:repo/git/commits/:sha", Name: ""},
+		{Method: "POST", Path: "/repos/:owner/:repo/git/commits", Name: ""},
+		//{Method: "GET", Path: "/repos/:owner/:repo/git/refs/*ref", Name: ""},
+		{Method: "GET", Path: "/repos/:owner/:repo/git/refs", Name: ""},
+		{Method: "POST", Path: "/repos/:owner/:repo/git/refs", Name: ""},
+		//{Method: "PATCH", Path: "/repos/:owner/:repo/git/refs/*ref", Name: ""},
+		//{Method: "DELETE", Path: "/repos/:owner/:repo/git/refs/*ref", Name: ""},
+		{Method: "GET", Path: "/repos/:owner/:repo/git/tags/:sha", Name: ""},
+		{Method: "POST", Path: "/repos/:owner/:repo/git/tags", Name: ""},
+		{Method: "GET", Path: "/repos/:owner/:repo/git/trees/:sha", Name: ""},
+		{Method: "POST", Path: "/repos/:owner/:repo/git/trees", Name: ""},
 
 		// Issues
-		{"GET", "/issues", ""},
-		{"GET", "/user/issues", ""},
-		{"GET", "/orgs/:org/issues", ""},
-		{"GET", "/repos/:owner/:repo/issues", ""},
-		{"GET", "/repos/:owner/:repo/issues/:number", ""},
-		{"POST", "/repos/:owner/:repo/issues", ""},
-		//{"PATCH", "/repos/:owner/:repo/issues/:number", ""},
-		{"GET", "/repos/:owner/:repo/assignees", ""},
-		{"GET", "/repos/:owner/:repo/assignees/:assignee", ""},
-		{"GET", "/repos/:owner/:repo/issues/:number/comments", ""},
-		//{"GET", "/repos/:owner/:repo/issues/comments", ""},
-		//{"GET", "/repos/:owner/:repo/issues/comments/:id", ""},
-		{"POST", "/repos/:owner/:repo/issues/:number/comments", ""},
-		//{"PATCH", "/repos/:owner/:repo/issues/comments/:id", ""},
-		//{"DELETE", "/repos/:owner/:repo/issues/comments/:id", ""},
-		{"GET", "/repos/:owner/:repo/issues/:number/events", ""},
-		//{"GET", "/repos/:owner/:repo/issues/events", ""},
-		//{"GET", "/repos/:owner/:repo/issues/events/:id", ""},
-		{"GET", "/repos/:owner/:repo/labels", ""},
-		{"GET", "/repos/:owner/:repo/labels/:name", ""},
-		{"POST", "/repos/:owner/:repo/labels", ""},
-		//{"PATCH", "/repos/:owner/:repo/labels/:name", ""},
-		{"DELETE", "/repos/:owner/:repo/labels/:name", ""},
-		{"GET", "/repos/:owner/:repo/issues/:number/labels", ""},
-		{"POST", "/repos/:owner/:repo/issues/:number/labels", ""},
-		{"DELETE", "/repos/:owner/:repo/issues/:number/labels/:name", ""},
-		{"PUT", "/repos/:owner/:repo/issues/:number/labels", ""},
-		{"DELETE", "/repos/:owner/:repo/issues/:number/labels", ""},
-		{"GET", "/repos/:owner/:repo/milestones/:number/labels", ""},
-		{"GET", "/repos/:owner/:repo/milestones", ""},
-		{"GET", "/repos/:owner/:repo/milestones/:number", ""},
-		{"POST", "/repos/:owner/:repo/milestones", ""},
-		//{"PATCH", "/repos/:owner/:repo/milestones/:number", ""},
-		{"DELETE", "/repos/:owner/:repo/milestones/:number", ""},
+		{Method: "GET", Path: "/issues", Name: ""},
+		{Method: "GET", Path: "/user/issues", Name: ""},
+		{Method: "GET", Path: "/orgs/:org/issues", Name: ""},
+		{Method: "GET", Path: "/repos/:owner/:repo/issues", Name: ""},
+		{Method: "GET", Path: "/repos/:owner/:repo/issues/:number", Name: ""},
+		{Method: "POST", Path: "/repos/:owner/:repo/issues", Name: ""},
+		//{Method: "PATCH", Path: "/repos/:owner/:repo/issues/:number", Name: ""},
+		{Method: "GET", Path: "/repos/:owner/:repo/assignees", Name: ""},
+		{Method: "GET", Path: "/repos/:owner/:repo/assignees/:assignee", Name: ""},
+		{Method: "GET", Path: "/repos/:owner/:repo/issues/:number/comments", Name: ""},
+		//{Method: "GET", Path: "/repos/:owner/:repo/issues/comments", Name: ""},
+		//{Method: "GET", Path: "/repos/:owner/:repo/issues/comments/:id", Name: ""},
+		{Method: "POST", Path: "/repos/:owner/:repo/issues/:number/comments", Name: ""},
+		//{Method: "PATCH", Path: "/repos/:owner/:repo/issues/comments/:id", Name: ""},
+		//{Method: "DELETE", Path: "/repos/:owner/:repo/issues/comments/:id", Name: ""},
+		{Method: "GET", Path: "/repos/:owner/:repo/issues/:number/events", Name: ""},
+		//{Method: "GET", Path: "/repos/:owner/:repo/issues/events", Name: ""},
+		//{Method: "GET", Path: "/repos/:owner/:repo/issues/events/:id", Name: ""},
+		{Method: "GET", Path: "/repos/:owner/:repo/labels", Name: ""},
+		{Method: "GET", Path: "/repos/:owner/:repo/labels/:name", Name: ""},
+		{Method: "POST", Path: "/repos/:owner/:repo/labels", Name: ""},
+		//{Method: "PATCH", Path: "/repos/:owner/:repo/labels/:name", Name: ""},
+		{Method: "DELETE", Path: "/repos/:owner/:repo/labels/:name", Name: ""},
+		{Method: "GET", Path: "/repos/:owner/:repo/issues/:number/labels", Name: ""},
+		{Method: "POST", Path: "/repos/:owner/:repo/issues/:number/labels", Name: ""},
+		{Method: "DELETE", Path: "/repos/:owner/:repo/issues/:number/labels/:name", Name: ""},
+		{Method: "PUT", Path: "/repos/:owner/:repo/issues/:number/labels", Name: ""},
+		{Method: "DELETE", Path: "/repos/:owner/:repo/issues/:number/labels", Name: ""},
+		{Method: "GET", Path: "/repos/:owner/:repo/milestones/:number/labels", Name: ""},
+		{Method: "GET", Path: "/repos/:owner/:repo/milestones", Name: ""},
+		{Method: "GET", Path: "/repos/:owner/:repo/milestones/:number", Name: ""},
+		{Method: "POST", Path: "/repos/:owner/:repo/milestones", Name: ""},
+		//{Method: "PATCH", Path: "/repos/:owner/:repo/milestones/:number", Name: ""},
+		{Method: "DELETE", Path: "/repos/:owner/:repo/milestones/:number", Name: ""},
 
 		// Miscellaneous
-		{"GET", "/emojis", ""},
-		{"GET", "/gitignore/templates", ""},
-		{"GET", "/gitignore/templates/:name", ""},
-		{"POST", "/markdown", ""},
-		{"POST", "/markdown/raw", ""},
-		{"GET", "/meta", ""},
-		{"GET", "/rate_limit", ""},
+		{Method: "GET", Path: "/emojis", Name: ""},
+		{Method: "GET", Path: "/gitignore/templates", Name: ""},
+		{Method: "GET", Path: "/gitignore/templates/:name", Name: ""},
+		{Method: "POST", Path: "/markdown", Name: ""},
+		{Method: "POST", Path: "/markdown/raw", Name: ""},
+		{Method: "GET", Path: "/meta", Name: ""},
+		{Method: "GET", Path: "/rate_limit", Name: ""},
 
 		// Organizations
-		{"GET", "/users/:user/orgs", ""},
-		{"GET", "/user/orgs", ""},
-		{"GET", "/orgs/:org", ""},
-		//{"PATCH", "/orgs/:org", ""},
-		{"GET", "/orgs/:org/members", ""},
-		{"GET", "/orgs/:org/members/:user", ""},
-		{"DELETE", "/orgs/:org/members/:user", ""},
-		{"GET", "/orgs/:org/public_members", ""},
-		{"GET", "/orgs/:org/public_members/:user", ""},
-		{"PUT", "/orgs/:org/public_members/:user", ""},
-		{"DELETE", "/orgs/:org/public_members/:user", ""},
-		{"GET", "/orgs/:org/teams", ""},
-		{"GET", "/teams/:id", ""},
-		{"POST", "/orgs/:org/teams", ""},
-		//{"PATCH", "/teams/:id", ""},
-		{"DELETE", "/teams/:id", ""},
-		{"GET", "/teams/:id/members", ""},
-		{"GET", "/teams/:id/members/:user", ""},
-		{"PUT", "/teams/:id/members/:user", ""},
-		{"DELETE", "/teams/:id/members/:user", ""},
-		{"GET", "/teams/:id/repos", ""},
-		{"GET", "/teams/:id/repos/:owner/:repo", ""},
-		{"PUT", "/teams/:id/repos/:owner/:repo", ""},
-		{"DELETE", "/teams/:id/repos/:owner/:repo", ""},
-		{"GET", "/user/teams", ""},
+		{Method: "GET", Path: "/users/:user/orgs", Name: ""},
+		{Method: "GET", Path: "/user/orgs", Name: ""},
+		{Method: "GET", Path: "/orgs/:org", Name: ""},
+		//{Method: "PATCH", Path: "/orgs/:org", Name: ""},
+		{Method: "GET", Path: "/orgs/:org/members", Name: ""},
+		{Method: "GET", Path: "/orgs/:org/members/:user", Name: ""},
+		{Method: "DELETE", Path: "/orgs/:org/members/:user", Name: ""},
+		{Method: "GET", Path: "/orgs/:org/public_members", Name: ""},
+		{Method: "GET", Path: "/orgs/:org/public_members/:user", Name: ""},
+		{Method: "PUT", Path: "/orgs/:org/public_members/:user", Name: ""},
+		{Method: "DELETE", Path: "/orgs/:org/public_members/:user", Name: ""},
+		{Method: "GET", Path: "/orgs/:org/teams", Name: ""},
+		{Method: "GET", Path: "/teams/:id", Name: ""},
+		{Method: "POST", Path: "/orgs/:org/teams", Name: ""},
+		//{Method: "PATCH", Path: "/teams/:id", Name: ""},
+		{Method: "DELETE", Path: "/teams/:id", Name: ""},
+		{Method: "GET", Path: "/teams/:id/members", Name: ""},
+		{Method: "GET", Path: "/teams/:id/members/:user", Name: ""},
+		{Method: "PUT", Path: "/teams/:id/members/:user", Name: ""},
+		{Method: "DELETE", Path: "/teams/:id/members/:user", Name: ""},
+		{Method: "GET", Path: "/teams/:id/repos", Name: ""},
+		{Method: "GET", Path: "/teams/:id/repos/:owner/:repo", Name: ""},
+		{Method: "PUT", Path: "/teams/:id/repos/:owner/:repo", Name: ""},
+		{Method: "DELETE", Path: "/teams/:id/repos/:owner/:repo", Name: ""},
+		{Method: "GET", Path: "/user/teams", Name: ""},
 
 		// Pull Requests
-		{"GET", "/repos/:owner/:repo/pulls", ""},
-		{"GET", "/repos/:owner/:repo/pulls/:number", ""},
-		{"POST", "/repos/:owner/:repo/pulls", ""},
-		//{"PATCH", "/repos/:owner/:repo/pulls/:number", ""},
-		{"GET", "/repos/:owner/:repo/pulls/:number/commits", ""},
-		{"GET", "/repos/:owner/:repo/pulls/:number/files", ""},
-		{"GET", "/repos/:owner/:repo/pulls/:number/merge", ""},
-		{"PUT", "/repos/:owner/:repo/pulls/:number/merge", ""},
-		{"GET", "/repos/:owner/:repo/pulls/:number/comments", ""},
-		//{"GET", "/repos/:owner/:repo/pulls/comments", ""},
-		//{"GET", "/repos/:owner/:repo/pulls/comments/:number", ""},
-		{"PUT", "/repos/:owner/:repo/pulls/:number/comments", ""},
-		//{"PATCH", "/repos/:owner/:repo/pulls/comments/:number", ""},
-		//{"DELETE", "/repos/:owner/:repo/pulls/comments/:number", ""},
+		{Method: "GET", Path: "/repos/:owner/:repo/pulls", Name: ""},
+		{Method: "GET", Path: "/repos/:owner/:repo/pulls/:number", Name: ""},
+		{Method: "POST", Path: "/repos/:owner/:repo/pulls", Name: ""},
+		//{Method: "PATCH", Path: "/repos/:owner/:repo/pulls/:number", Name: ""},
+		{Method: "GET", Path: "/repos/:owner/:repo/pulls/:number/commits", Name: ""},
+		{Method: "GET", Path: "/repos/:owner/:repo/pulls/:number/files", Name: ""},
+		{Method: "GET", Path: "/repos/:owner/:repo/pulls/:number/merge", Name: ""},
+		{Method: "PUT", Path: "/repos/:owner/:repo/pulls/:number/merge", Name: ""},
+		{Method: "GET", Path: "/repos/:owner/:repo/pulls/:number/comments", Name: ""},
+		//{Method: "GET", Path: "/repos/:owner/:repo/pulls/comments", Name: ""},
+		//{Method: "GET", Path: "/repos/:owner/:repo/pulls/comments/:number", Name: ""},
+		{Method: "PUT", Path: "/repos/:owner/:repo/pulls/:number/comments", Name: ""},
+		//{Method: "PATCH", Path: "/repos/:owner/:repo/pulls/comments/:number", Name: ""},
+		//{Method: "DELETE", Path: "/repos/:owner/:repo/pulls/comments/:number", Name: ""},
 
 		// Repositories
-		{"GET", "/user/repos", ""},
-		{"GET", "/users/:user/repos", ""},
-		{"GET", "/orgs/:org/repos", ""},
-		{"GET", "/repositories", ""},
-		{"POST", "/user/repos", ""},
-		{"POST", "/orgs/:org/repos", ""},
-		{"GET", "/repos/:owner/:repo", ""},
-		//{"PATCH", "/repos/:owner/:repo", ""},
-		{"GET", "/repos/:owner/:repo/contributors", ""},
-		{"GET", "/repos/:owner/:repo/languages", ""},
-		{"GET", "/repos/:owner/:repo/teams", ""},
-		{"GET", "/repos/:owner/:repo/tags", ""},
-		{"GET", "/repos/:owner/:repo/branches", ""},
-		{"GET", "/repos/:owner/:repo/branches/:branch", ""},
-		{"DELETE", "/repos/:owner/:repo", ""},
-		{"GET", "/repos/:owner/:repo/collaborators", ""},
-		{"GET", "/repos/:owner/:repo/collaborators/:user", ""},
-		{"PUT", "/repos/:owner/:repo/collaborators/:user", ""},
-		{"DELETE", "/repos/:owner/:repo/collaborators/:user", ""},
-		{"GET", "/repos/:owner/:repo/comments", ""},
-		{"GET", "/repos/:owner/:repo/commits/:sha/comments", ""},
-		{"POST", "/repos/:owner/:repo/commits/:sha/comments", ""},
-		{"GET", "/repos/:owner/:repo/comments/:id", ""},
-		//{"PATCH", "/repos/:owner/:repo/comments/:id", ""},
-		{"DELETE", "/repos/:owner/:repo/comments/:id", ""},
-		{"GET", "/repos/:owner/:repo/commits", ""},
-		{"GET", "/repos/:owner/:repo/commits/:sha", ""},
-		{"GET", "/repos/:owner/:repo/readme", ""},
-		//{"GET", "/repos/:owner/:repo/contents/*path", ""},
-		//{"PUT", "/repos/:owner/:repo/contents/*path", ""},
-		//{"DELETE", "/repos/:owner/:repo/contents/*path", ""},
-		//{"GET", "/repos/:owner/:repo/:archive_format/:ref", ""},
-		{"GET", "/repos/:owner/:repo/keys", ""},
-		{"GET", "/repos/:owner/:repo/keys/:id", ""},
-		{"POST", "/repos/:owner/:repo/keys", ""},
-		//{"PATCH", "/repos/:owner/:repo/keys/:id", ""},
-		{"DELETE", "/repos/:owner/:repo/keys/:id", ""},
-		{"GET", "/repos/:owner/:repo/downloads", ""},
-		{"GET", "/repos/:owner/:repo/downloads/:id", ""},
-		{"DELETE", "/repos/:owner/:repo/downloads/:id", ""},
-		{"GET", "/repos/:owner/:repo/forks", ""},
-		{"POST", "/repos/:owner/:repo/forks", ""},
-		{"GET", "/repos/:owner/:repo/hooks", ""},
-		{"GET", "/repos/:owner/:repo/hooks/:id", ""},
-		{"POST", "/repos/:owner/:repo/hooks", ""},
-		//{"PATCH", "/repos/:owner/:repo/hooks/:id", ""},
-		{"POST", "/repos/:owner/:repo/hooks/:id/tests", ""},
-		{"DELETE", "/repos/:owner/:repo/hooks/:id", ""},
-		{"POST", "/repos/:owner/:repo/merges", ""},
-		{"GET", "/repos/:owner/:repo/releases", ""},
-		{"GET", "/repos/:owner/:repo/releases/:id", ""},
-		{"POST", "/repos/:owner/:repo/releases", ""},
-		//{"PATCH", "/repos/:owner/:repo/releases/:id", ""},
-		{"DELETE", "/repos/:owner/:repo/releases/:id", ""},
-		{"GET", "/repos/:owner/:repo/releases/:id/assets", ""},
-		{"GET", "/repos/:owner/:repo/stats/contributors", ""},
-		{"GET", "/repos/:owner/:repo/stats/commit_activity", ""},
-		{"GET", "/repos/:owner/:repo/stats/code_frequency", ""},
-		{"GET", "/repos/:owner/:repo/stats/participation", ""},
-		{"GET", "/repos/:owner/:repo/stats/punch_card", ""},
-		{"GET", "/repos/:owner/:repo/statuses/:ref", ""},
-		{"POST", "/repos/:owner/:repo/statuses/:ref", ""},
+		{Method: "GET", Path: "/user/repos", Name: ""},
+		{Method: "GET", Path: "/users/:user/repos", Name: ""},
+		{Method: "GET", Path: "/orgs/:org/repos", Name: ""},
+		{Method: "GET", Path: "/repositories", Name: ""},
+		{Method: "POST", Path: "/user/repos", Name: ""},
+		{Method: "POST", Path: "/orgs/:org/repos", Name: ""},
+		{Method: "GET", Path: "/repos/:owner/:repo", Name: ""},
+		//{Method: "PATCH", Path: "/repos/:owner/:repo", Name: ""},
+		{Method: "GET", Path: "/repos/:owner/:repo/contributors", Name: ""},
+		{Method: "GET", Path: "/repos/:owner/:repo/languages", Name: ""},
+		{Method: "GET", Path: "/repos/:owner/:repo/teams", Name: ""},
+		{Method: "GET", Path: "/repos/:owner/:repo/tags", Name: ""},
+		{Method: "GET", Path: "/repos/:owner/:repo/branches", Name: ""},
+		{Method: "GET", Path: "/repos/:owner/:repo/branches/:branch", Name: ""},
+		{Method: "DELETE", Path: "/repos/:owner/:repo", Name: ""},
+		{Method: "GET", Path: "/repos/:owner/:repo/collaborators", Name: ""},
+		{Method: "GET", Path: "/repos/:owner/:repo/collaborators/:user", Name: ""},
+		{Method: "PUT", Path: "/repos/:owner/:repo/collaborators/:user", Name: ""},
+		{Method: "DELETE", Path: "/repos/:owner/:repo/collaborators/:user", Name: ""},
+		{Method: "GET", Path: "/repos/:owner/:repo/comments", Name: ""},
+		{Method: "GET", Path: "/repos/:owner/:repo/commits/:sha/comments", Name: ""},
+		{Method: "POST", Path: "/repos/:owner/:repo/commits/:sha/comments", Name: ""},
+		{Method: "GET", Path: "/repos/:owner/:repo/comments/:id", Name: ""},
+		//{Method: "PATCH", Path: "/repos/:owner/:repo/comments/:id", Name: ""},
+		{Method: "DELETE", Path: "/repos/:owner/:repo/comments/:id", Name: ""},
+		{Method: "GET", Path: "/repos/:owner/:repo/commits", Name: ""},
+		{Method: "GET", Path: "/repos/:owner/:repo/commits/:sha", Name: ""},
+		{Method: "GET", Path: "/repos/:owner/:repo/readme", Name: ""},
+		//{Method: "GET", Path: "/repos/:owner/:repo/contents/*path", Name: ""},
+		//{Method: "PUT", Path: "/repos/:owner/:repo/contents/*path", Name: ""},
+		//{Method: "DELETE", Path: "/repos/:owner/:repo/contents/*path", Name: ""},
+		//{Method: "GET", Path: "/repos/:owner/:repo/:archive_format/:ref", Name: ""},
+		{Method: "GET", Path: "/repos/:owner/:repo/keys", Name: ""},
+		{Method: "GET", Path: "/repos/:owner/:repo/keys/:id", Name: ""},
+		{Method: "POST", Path: "/repos/:owner/:repo/keys", Name: ""},
+		//{Method: "PATCH", Path: "/repos/:owner/:repo/keys/:id", Name: ""},
+		{Method: "DELETE", Path: "/repos/:owner/:repo/keys/:id", Name: ""},
+		{Method: "GET", Path: "/repos/:owner/:repo/downloads", Name: ""},
+		{Method: "GET", Path: "/repos/:owner/:repo/downloads/:id", Name: ""},
+		{Method: "DELETE", Path: "/repos/:owner/:repo/downloads/:id", Name: ""},
+		{Method: "GET", Path: "/repos/:owner/:repo/forks", Name: ""},
+		{Method: "POST", Path: "/repos/:owner/:repo/forks", Name: ""},
+		{Method: "GET", Path: "/repos/:owner/:repo/hooks", Name: ""},
+		{Method: "GET", Path: "/repos/:owner/:repo/hooks/:id", Name: ""},
+		{Method: "POST", Path: "/repos/:owner/:repo/hooks", Name: ""},
+		//{Method: "PATCH", Path: "/repos/:owner/:repo/hooks/:id", Name: ""},
+		{Method: "POST", Path: "/repos/:owner/:repo/hooks/:id/tests", Name: ""},
+		{Method: "DELETE", Path: "/repos/:owner/:repo/hooks/:id", Name: ""},
+		{Method: "POST", Path: "/repos/:owner/:repo/merges", Name: ""},
+		{Method: "GET", Path: "/repos/:owner/:repo/releases", Name: ""},
+		{Method: "GET", Path: "/repos/:owner/:repo/releases/:id", Name: ""},
+		{Method: "POST", Path: "/repos/:owner/:repo/releases", Name: ""},
+		//{Method: "PATCH", Path: "/repos/:owner/:repo/releases/:id", Name: ""},
+		{Method: "DELETE", Path: "/repos/:owner/:repo/releases/:id", Name: ""},
+		{Method: "GET", Path: "/repos/:owner/:repo/releases/:id/assets", Name: ""},
+		{Method: "GET", Path: "/repos/:owner/:repo/stats/contributors", Name: ""},
+		{Method: "GET", Path: "/repos/:owner/:repo/stats/commit_activity", Name: ""},
+		{Method: "GET", Path: "/repos/:owner/:repo/stats/code_frequency", Name: ""},
+		{Method: "GET", Path: "/repos/:owner/:repo/stats/participation", Name: ""},
+		{Method: "GET", Path: "/repos/:owner/:repo/stats/punch_card", Name: ""},
+		{Method: "GET", Path: "/repos/:owner/:repo/statuses/:ref", Name: ""},
+		{Method: "POST", Path: "/repos/:owner/:repo/statuses/:ref", Name: ""},
 
 		// Search
-		{"GET", "/search/repositories", ""},
-		{"GET", "/search/code", ""},
-		{"GET", "/search/issues", ""},
-		{"GET", "/search/users", ""},
-		{"GET", "/legacy/issues/search/:owner/:repository/:state/:keyword", ""},
-		{"GET", "/legacy/repos/search/:keyword", ""},
-		{"GET", "/legacy/user/search/:keyword", ""},
-		{"GET", "/legacy/user/email/:email", ""},
+		{Method: "GET", Path: "/search/repositories", Name: ""},
+		{Method: "GET", Path: "/search/code", Name: ""},
+		{Method: "GET", Path: "/search/issues", Name: ""},
+		{Method: "GET", Path: "/search/users", Name: ""},
+		{Method: "GET", Path: "/legacy/issues/search/:owner/:repository/:state/:keyword", Name: ""},
+		{Method: "GET", Path: "/legacy/repos/search/:keyword", Name: ""},
+		{Method: "GET", Path: "/legacy/user/search/:keyword", Name: ""},
+		{Method: "GET", Path: "/legacy/user/email/:email", Name: ""},
 
 		// Users
-		{"GET", "/users/:user", ""},
-		{"GET", "/user", ""},
-		//{"PATCH", "/user", ""},
-		{"GET", "/users", ""},
-		{"GET", "/user/emails", ""},
-		{"POST", "/user/emails", ""},
-		{"DELETE", "/user/emails", ""},
-		{"GET", "/users/:user/followers", ""},
-		{"GET", "/user/followers", ""},
-		{"GET", "/users/:user/following", ""},
-		{"GET", "/user/following", ""},
-		{"GET", "/user/following/:user", ""},
-		{"GET", "/users/:user/following/:target_user", ""},
-		{"PUT", "/user/following/:user", ""},
-		{"DELETE", "/user/following/:user", ""},
-		{"GET", "/users/:user/keys", ""},
-		{"GET", "/user/keys", ""},
-		{"GET", "/user/keys/:id", ""},
-		{"POST", "/user/keys", ""},
-		//{"PATCH", "/user/keys/:id", ""},
-		{"DELETE", "/user/keys/:id", ""},
+		{Method: "GET", Path: "/users/:user", Name: ""},
+		{Method: "GET", Path: "/user", Name: ""},
+		//{Method: "PATCH", Path: "/user", Name: ""},
+		{Method: "GET", Path: "/users", Name: ""},
+		{Method: "GET", Path: "/user/emails", Name: ""},
+		{Method: "POST", Path: "/user/emails", Name: ""},
+		{Method: "DELETE", Path: "/user/emails", Name: ""},
+		{Method: "GET", Path: "/users/:user/followers", Name: ""},
+		{Method: "GET", Path: "/user/followers", Name: ""},
+		{Method: "GET", Path: "/users/:user/following", Name: ""},
+		{Method: "GET", Path: "/user/following", Name: ""},
+		{Method: "GET", Path: "/user/following/:user", Name: ""},
+		{Method: "GET", Path: "/users/:user/following/:target_user", Name: ""},
+		{Method: "PUT", Path: "/user/following/:user", Name: ""},
+		{Method: "DELETE", Path: "/user/following/:user", Name: ""},
+		{Method: "GET", Path: "/users/:user/keys", Name: ""},
+		{Method: "GET", Path: "/user/keys", Name: ""},
+		{Method: "GET", Path: "/user/keys/:id", Name: ""},
+		{Method: "POST", Path: "/user/keys", Name: ""},
+		//{Method: "PATCH", Path: "/user/keys/:id", Name: ""},
+		{Method: "DELETE", Path: "/user/keys/:id", Name: ""},
 	}
 
 	parseAPI = []*Route{
 		// Objects
-		{"POST", "/1/classes/:className", ""},
-		{"GET", "/1/classes/:className/:objectId", ""},
-		{"PUT", "/1/classes/:className/:objectId", ""},
-		{"GET", "/1/classes/:className", ""},
-		{"DELETE", "/1/classes/:className/:objectId", ""},
+		{Method: "POST", Path: "/1/classes/:className", Name: ""},
+		{Method: "GET", Path: "/1/classes/:className/:objectId", Name: ""},
+		{Method: "PUT", Path: "/1/classes/:className/:objectId", Name: ""},
+		{Method: "GET", Path: "/1/classes/:className", Name: ""},
+		{Method: "DELETE", Path: "/1/classes/:className/:objectId", Name: ""},
 
 		// Users
-		{"POST", "/1/users", ""},
-		{"GET", "/1/login", ""},
-		{"GET", "/1/users/:objectId", ""},
-		{"PUT", "/1/users/:objectId", ""},
-		{"GET", "/1/users", ""},
-		{"DELETE", "/1/users/:objectId", ""},
-		{"POST", "/1/requestPasswordReset", ""},
+		{Method: "POST", Path: "/1/users", Name: ""},
+		{Method: "GET", Path: "/1/login", Name: ""},
+		{Method: "GET", Path: "/1/users/:objectId", Name: ""},
+		{Method: "PUT", Path: "/1/users/:objectId", Name: ""},
+		{Method: "GET", Path: "/1/users", Name: ""},
+		{Method: "DELETE", Path: "/1/users/:objectId", Name: ""},
+		{Method: "POST", Path: "/1/requestPasswordReset", Name: ""},
 
 		// Roles
-		{"POST", "/1/roles", ""},
-		{"GET", "/1/roles/:objectId", ""},
-		{"PUT", "/1/roles/:objectId", ""},
-		{"GET", "/1/roles", ""},
-		{"DELETE", "/1/roles/:objectId", ""},
+		{Method: "POST", Path: "/1/roles", Name: ""},
+		{Method: "GET", Path: "/1/roles/:objectId", Name: ""},
+		{Method: "PUT", Path: "/1/roles/:objectId", Name: ""},
+		{Method: "GET", Path: "/1/roles", Name: ""},
+		{Method: "DELETE", Path: "/1/roles/:objectId", Name: ""},
 
 		// Files
-		{"POST", "/1/files/:fileName", ""},
+		{Method: "POST", Path: "/1/files/:fileName", Name: ""},
 
 		// Analytics
-		{"POST", "/1/events/:eventName", ""},
+		{Method: "POST", Path: "/1/events/:eventName", Name: ""},
 
 		// Push Notifications
-		{"POST", "/1/push", ""},
+		{Method: "POST", Path: "/1/push", Name: ""},
 
 		// Installations
-		{"POST", "/1/installations", ""},
-		{"GET", "/1/installations/:objectId", ""},
-		{"PUT", "/1/installations/:objectId", ""},
-		{"GET", "/1/installations", ""},
-		{"DELETE", "/1/installations/:objectId", ""},
+		{Method: "POST", Path: "/1/installations", Name: ""},
+		{Method: "GET", Path: "/1/installations/:objectId", Name: ""},
+		{Method: "PUT", Path: "/1/installations/:objectId", Name: ""},
+		{Method: "GET", Path: "/1/installations", Name: ""},
+		{Method: "DELETE", Path: "/1/installations/:objectId", Name: ""},
 
 		// Cloud Functions
-		{"POST", "/1/functions", ""},
+		{Method: "POST", Path: "/1/functions", Name: ""},
 	}
 
 	googlePlusAPI = []*Route{
 		// People
-		{"GET", "/people/:userId", ""},
-		{"GET", "/people", ""},
-		{"GET", "/activities/:activityId/people/:collection", ""},
-		{"GET", "/people/:userId/people/:collection", ""},
-		{"GET", "/people/:userId/openIdConnect", ""},
+		{Method: "GET", Path: "/people/:userId", Name: ""},
+		{Method: "GET", Path: "/people", Name: ""},
+		{Method: "GET", Path: "/activities/:activityId/people/:collection", Name: ""},
+		{Method: "GET", Path: "/people/:userId/people/:collection", Name: ""},
+		{Method: "GET", Path: "/people/:userId/openIdConnect", Name: ""},
 
 		// Activities
-		{"GET", "/people/:userId/activities/:collection", ""},
-		{"GET", "/activities/:activityId", ""},
-		{"GET", "/activities", ""},
+		{Method: "GET", Path: "/people/:userId/activities/:collection", Name: ""},
+		{Method: "GET", Path: "/activities/:activityId", Name: ""},
+		{Method: "GET", Path: "/activities", Name: ""},
 
 		// Comments
-		{"GET", "/activities/:activityId/comments", ""},
-		{"GET", "/comments/:commentId", ""},
+		{Method: "GET", Path: "/activities/:activityId/comments", Name: ""},
+		{Method: "GET", Path: "/comments/:commentId", Name: ""},
 
 		// Moments
-		{"POST", "/people/:userId/moments/:collection", ""},
-		{"GET", "/people/:userId/moments/:collection", ""},
-		{"DELETE", "/moments/:id", ""},
+		{Method: "POST", Path: "/people/:userId/moments/:collection", Name: ""},
+		{Method: "GET", Path: "/people/:userId/moments/:collection", Name: ""},
+		{Method: "DELETE", Path: "/moments/:id", Name: ""},
 	}
 )
 
@@ -526,6 +527,111 @@ func TestRouterParam(t *testing.T) {
 	assert.Equal(t, "1", c.Param("id"))
 }
 
+func TestRouterParamConstraints(t *testing.T) {
+	e := New()
+	h := func(c Context) error {
+		return c.String(http.StatusOK, c.Param("id")+c.Param("name"))
+	}
+	e.GET("/users/:id<int>", h)
+	e.GET("/files/:name<regex([a-z]+\\.png)>/raw", h).Name = "file"
+
+	for path, code := range map[string]int{
+		"/users/42":          http.StatusOK,
+		"/users/-1":          http.StatusOK,
+		"/users/abc":         http.StatusNotFound,
+		"/files/a.png/raw":   http.StatusOK,
+		"/files/a.jpg/raw":   http.StatusNotFound,
+		"/files/xa.pngx/raw": http.StatusNotFound,
+	} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		assert.Equal(t, code, rec.Code, path)
+	}
+	assert.Equal(t, "/files/x.png/raw", e.Reverse("file", "x.png"))
+
+	assert.Panics(t, func() {
+		e.GET("/a/:id<float>", h)
+	})
+	assert.Panics(t, func() {
+		e.GET("/a/:id<regex([a-z)>", h)
+	})
+
+	// Conflicting params and constraints of the same node
+	assert.Panics(t, func() {
+		e.GET("/users/:name<alpha>", h)
+	})
+	assert.Panics(t, func() {
+		e.DELETE("/users/:name", h)
+	})
+	assert.Panics(t, func() {
+		e.GET("/users/:id<uint>", h)
+	})
+	assert.NotPanics(t, func() {
+		e.DELETE("/users/:id<uint>", h)
+		e.GET("/users/:id<int>", h)
+		e.GET("/items/:id", h)
+		e.PUT("/items/:name", h)
+	})
+	code, body := request(http.MethodGet, "/users/12", e)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "12", body)
+}
+
+func TestRouterCaseInsensitive(t *testing.T) {
+	e := New()
+	e.CaseInsensitivePaths = true
+	h := func(c Context) error {
+		return c.String(http.StatusOK, c.Path()+" "+strings.Join(c.ParamValues(), ","))
+	}
+	e.GET("/Users/:userID/Files/*", h)
+	e.GET("/about", h)
+
+	for path, body := range map[string]string{
+		"/users/Jon/files/A.txt": "/Users/:userID/Files/* Jon,A.txt",
+		"/USERS/Jon/FILES/":      "/Users/:userID/Files/* Jon,",
+		"/About":                 "/about ",
+	} {
+		code, b := request(http.MethodGet, path, e)
+		assert.Equal(t, http.StatusOK, code, path)
+		assert.Equal(t, body, b, path)
+	}
+	code, _ := request(http.MethodGet, "/users", e)
+	assert.Equal(t, http.StatusNotFound, code)
+
+	e.RedirectCaseInsensitivePaths = true
+	e.GET("/Docs/:name", h)
+	req := httptest.NewRequest(http.MethodGet, "/docs/Intro?v=1", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusMovedPermanently, rec.Code)
+	assert.Equal(t, "/Docs/Intro?v=1", rec.Header().Get(HeaderLocation))
+	code, _ = request(http.MethodGet, "/Docs/Intro", e)
+	assert.Equal(t, http.StatusOK, code)
+}
+
+func TestRouterCaseInsensitiveMixed(t *testing.T) {
+	e := New()
+	h := func(c Context) error {
+		return c.String(http.StatusOK, c.Path())
+	}
+	e.GET("/a", h)
+	e.POST("/c", h)
+	e.CaseInsensitivePaths = true
+	e.GET("/B", h)
+
+	for path, code := range map[string]int{
+		"/a": http.StatusOK,
+		"/A": http.StatusNotFound,
+		"/b": http.StatusOK,
+		"/B": http.StatusOK,
+		"/c": http.StatusMethodNotAllowed,
+	} {
+		c, _ := request(http.MethodGet, path, e)
+		assert.Equal(t, code, c, path)
+	}
+}
+
 func TestRouterTwoParam(t *testing.T) {
 	e := New()
 	r := e.router
@@ -920,9 +1026,9 @@ func TestRouterGitHubAPI(t *testing.T) {
 // Issue #729
 func TestRouterParamAlias(t *testing.T) {
 	api := []*Route{
-		{http.MethodGet, "/users/:userID/following", ""},
-		{http.MethodGet, "/users/:userID/followedBy", ""},
-		{http.MethodGet, "/users/:userID/follow", ""},
+		{Method: http.MethodGet, Path: "/users/:userID/following", Name: ""},
+		{Method: http.MethodGet, Path: "/users/:userID/followedBy", Name: ""},
+		{Method: http.MethodGet, Path: "/users/:userID/follow", Name: ""},
 	}
 	testRouterAPI(t, api)
 }
@@ -930,21 +1036,21 @@ func TestRouterParamAlias(t *testing.T) {
 // Issue #1052
 func TestRouterParamOrdering(t *testing.T) {
 	api := []*Route{
-		{http.MethodGet, "/:a/:b/:c/:id", ""},
-		{http.MethodGet, "/:a/:id", ""},
-		{http.MethodGet, "/:a/:e/:id", ""},
+		{Method: http.MethodGet, Path: "/:a/:b/:c/:id", Name: ""},
+		{Method: http.MethodGet, Path: "/:a/:id", Name: ""},
+		{Method: http.MethodGet, Path: "/:a/:e/:id", Name: ""},
 	}
 	testRouterAPI(t, api)
 	api2 := []*Route{
-		{http.MethodGet, "/:a/:id", ""},
-		{http.MethodGet, "/:a/:e/:id", ""},
-		{http.MethodGet, "/:a/:b/:c/:id", ""},
+		{Method: http.MethodGet, Path: "/:a/:id", Name: ""},
+		{Method: http.MethodGet, Path: "/:a/:e/:id", Name: ""},
+		{Method: http.MethodGet, Path: "/:a/:b/:c/:id", Name: ""},
 	}
 	testRouterAPI(t, api2)
 	api3 := []*Route{
-		{http.MethodGet, "/:a/:b/:c/:id", ""},
-		{http.MethodGet, "/:a/:e/:id", ""},
-		{http.MethodGet, "/:a/:id", ""},
+		{Method: http.MethodGet, Path: "/:a/:b/:c/:id", Name: ""},
+		{Method: http.MethodGet, Path: "/:a/:e/:id", Name: ""},
+		{Method: http.MethodGet, Path: "/:a/:id", Name: ""},
 	}
 	testRouterAPI(t, api3)
 }
@@ -952,13 +1058,13 @@ func TestRouterParamOrdering(t *testing.T) {
 // Issue #1139
 func TestRouterMixedParams(t *testing.T) {
 	api := []*Route{
-		{http.MethodGet, "/teacher/:tid/room/suggestions", ""},
-		{http.MethodGet, "/teacher/:id", ""},
+		{Method: http.MethodGet, Path: "/teacher/:tid/room/suggestions", Name: ""},
+		{Method: http.MethodGet, Path: "/teacher/:id", Name: ""},
 	}
 	testRouterAPI(t, api)
 	api2 := []*Route{
-		{http.MethodGet, "/teacher/:id", ""},
-		{http.MethodGet, "/teacher/:tid/room/suggestions", ""},
+		{Method: http.MethodGet, Path: "/teacher/:id", Name: ""},
+		{Method: http.MethodGet, Path: "/teacher/:tid/room/suggestions", Name: ""},
 	}
 	testRouterAPI(t, api2)
 }
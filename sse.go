@@ -0,0 +1,164 @@
+package echo
+
+import (
+	stdContext "context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	// SSE is a Server-Sent Events stream, see `Context#SSE()`. Its methods are
+	// safe to call concurrently.
+	SSE struct {
+		response *Response
+		ctx      stdContext.Context
+		mutex    sync.Mutex
+		closed   bool
+		stop     chan struct{}
+		wg       sync.WaitGroup
+	}
+)
+
+var (
+	// DefaultSSEHeartbeat is the interval of the heartbeats sent by streams
+	// created with `Context#SSE()`, which keep proxies from closing idle
+	// connections.
+	DefaultSSEHeartbeat = 15 * time.Second
+
+	// ErrSSEClosed is returned when sending to a closed SSE stream.
+	ErrSSEClosed = errors.New("sse stream closed")
+)
+
+func newSSE(c Context) (*SSE, error) {
+	res := c.Response()
+	if _, ok := res.Writer.(http.Flusher); !ok {
+		return nil, errors.New("response writer doesn't support flushing")
+	}
+	h := res.Header()
+	h.Set(HeaderContentType, MIMETextEventStream)
+	h.Set(HeaderCacheControl, "no-cache")
+	h.Set(HeaderConnection, "keep-alive")
+	// Disables response buffering of nginx
+	h.Set("X-Accel-Buffering", "no")
+	res.WriteHeader(http.StatusOK)
+	res.Flush()
+
+	s := &SSE{response: res, ctx: c.Request().Context()}
+	s.Heartbeat(DefaultSSEHeartbeat)
+	return s, nil
+}
+
+// Send sends an event to the client and flushes it. Event and id are optional.
+// Data is sent as is if it's a string or []byte, and JSON encoded otherwise.
+// Sending fails once the client disconnected or the stream is closed.
+func (s *SSE) Send(event, id string, data interface{}) error {
+	if strings.ContainsAny(event, "\r\n") || strings.ContainsAny(id, "\r\n") {
+		return errors.New("sse event and id must not contain line breaks")
+	}
+	var payload string
+	switch d := data.(type) {
+	case string:
+		payload = d
+	case []byte:
+		payload = string(d)
+	default:
+		b, err := json.Marshal(d)
+		if err != nil {
+			return err
+		}
+		payload = string(b)
+	}
+
+	msg := new(strings.Builder)
+	if event != "" {
+		fmt.Fprintf(msg, "event: %s\n", event)
+	}
+	if id != "" {
+		fmt.Fprintf(msg, "id: %s\n", id)
+	}
+	payload = strings.Replace(payload, "\r\n", "\n", -1)
+	for _, line := range strings.Split(payload, "\n") {
+		fmt.Fprintf(msg, "data: %s\n", line)
+	}
+	msg.WriteString("\n")
+	return s.write(msg.String())
+}
+
+// Heartbeat changes the interval of the heartbeats, sent as comments which
+// clients ignore. An interval of zero disables them.
+func (s *SSE) Heartbeat(interval time.Duration) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.closed {
+		return
+	}
+	if s.stop != nil {
+		close(s.stop)
+		s.stop = nil
+	}
+	if interval <= 0 {
+		return
+	}
+	s.stop = make(chan struct{})
+	s.wg.Add(1)
+	go s.heartbeat(interval, s.stop)
+}
+
+func (s *SSE) heartbeat(interval time.Duration, stop chan struct{}) {
+	defer s.wg.Done()
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-s.ctx.Done():
+			return
+		case <-t.C:
+			if s.write(": heartbeat\n\n") != nil {
+				return
+			}
+		}
+	}
+}
+
+// Done returns a channel which is closed when the client disconnects.
+func (s *SSE) Done() <-chan struct{} {
+	return s.ctx.Done()
+}
+
+// Close stops the heartbeats, after which nothing is sent anymore. It must be
+// called before the handler returns, usually deferred.
+func (s *SSE) Close() {
+	s.mutex.Lock()
+	if !s.closed {
+		s.closed = true
+		if s.stop != nil {
+			close(s.stop)
+			s.stop = nil
+		}
+	}
+	s.mutex.Unlock()
+	s.wg.Wait()
+}
+
+func (s *SSE) write(msg string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.closed {
+		return ErrSSEClosed
+	}
+	if err := s.ctx.Err(); err != nil {
+		return err
+	}
+	if _, err := s.response.Write([]byte(msg)); err != nil {
+		return err
+	}
+	s.response.Flush()
+	return nil
+}
@@ -0,0 +1,90 @@
+package echo
+
+import (
+	"bufio"
+	stdContext "context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSSE(t *testing.T) {
+	e := New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	s, err := c.SSE()
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, MIMETextEventStream, rec.Header().Get(HeaderContentType))
+	assert.Equal(t, "no-cache", rec.Header().Get(HeaderCacheControl))
+
+	assert.NoError(t, s.Send("update", "1", "line 1\nline 2"))
+	assert.NoError(t, s.Send("", "", []byte("raw")))
+	assert.NoError(t, s.Send("user", "", user{1, "Jon Snow"}))
+	assert.Error(t, s.Send("bad\nevent", "", "x"))
+	s.Close()
+	assert.Equal(t, ErrSSEClosed, s.Send("", "", "x"))
+
+	assert.Equal(t, "event: update\nid: 1\ndata: line 1\ndata: line 2\n\n"+
+		"data: raw\n\n"+
+		"event: user\ndata: {\"id\":1,\"name\":\"Jon Snow\"}\n\n", rec.Body.String())
+	assert.True(t, rec.Flushed)
+}
+
+func TestSSEHeartbeat(t *testing.T) {
+	e := New()
+	e.GET("/", func(c Context) error {
+		s, err := c.SSE()
+		if err != nil {
+			return err
+		}
+		defer s.Close()
+		s.Heartbeat(10 * time.Millisecond)
+		<-s.Done()
+		return nil
+	})
+	srv := httptest.NewServer(e)
+	defer srv.Close()
+
+	ctx, cancel := stdContext.WithCancel(stdContext.Background())
+	req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+	res, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if !assert.NoError(t, err) {
+		cancel()
+		return
+	}
+	line, err := bufio.NewReader(res.Body).ReadString('\n')
+	assert.NoError(t, err)
+	assert.Equal(t, ": heartbeat\n", line)
+
+	// Client disconnects
+	cancel()
+	res.Body.Close()
+}
+
+func TestSSEDisconnected(t *testing.T) {
+	e := New()
+	ctx, cancel := stdContext.WithCancel(stdContext.Background())
+	defer cancel()
+	req := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	s, err := c.SSE()
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer s.Close()
+	cancel()
+	<-s.Done()
+	assert.Equal(t, stdContext.Canceled, s.Send("", "", "x"))
+	assert.False(t, strings.Contains(rec.Body.String(), "data"))
+}
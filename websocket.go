@@ -0,0 +1,405 @@
+package echo
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+type (
+	// WebSocketConn is a WebSocket connection, see `Context#WebSocket()`.
+	WebSocketConn interface {
+		// ReadMessage reads the next text or binary message. Pings are answered
+		// and pongs skipped while reading. Once the peer closes the
+		// connection, the close handshake is completed and a
+		// `*WebSocketCloseError` is returned.
+		ReadMessage() (messageType int, data []byte, err error)
+
+		// WriteMessage writes a text or binary message. It is safe to call
+		// concurrently with `ReadMessage()`.
+		WriteMessage(messageType int, data []byte) error
+
+		// Ping sends a ping with data, which must not exceed 125 bytes.
+		Ping(data []byte) error
+
+		// Close starts the close handshake with code and reason, waits for the
+		// peer to acknowledge it and closes the connection. It must not be
+		// called concurrently with `ReadMessage()`.
+		Close(code int, reason string) error
+
+		// Subprotocol returns the negotiated subprotocol, if any.
+		Subprotocol() string
+
+		// RemoteAddr returns the network address of the peer.
+		RemoteAddr() net.Addr
+	}
+
+	// WebSocketCloseError is returned by `WebSocketConn#ReadMessage()` when
+	// the connection was closed by the peer or because of a protocol error.
+	WebSocketCloseError struct {
+		Code   int
+		Reason string
+	}
+
+	webSocketConn struct {
+		conn        net.Conn
+		reader      *bufio.Reader
+		writer      *bufio.Writer
+		writeMutex  sync.Mutex
+		closeSent   bool
+		readLimit   int64
+		subprotocol string
+	}
+)
+
+// WebSocket message types
+const (
+	WebSocketTextMessage   = 1
+	WebSocketBinaryMessage = 2
+)
+
+// WebSocket close codes
+const (
+	WebSocketCloseNormal          = 1000
+	WebSocketCloseGoingAway       = 1001
+	WebSocketCloseProtocolError   = 1002
+	WebSocketCloseUnsupportedData = 1003
+	WebSocketCloseNoStatus        = 1005
+	WebSocketCloseInvalidPayload  = 1007
+	WebSocketCloseMessageTooBig   = 1009
+)
+
+const (
+	webSocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+	wsContinuation = 0x0
+	wsClose        = 0x8
+	wsPing         = 0x9
+	wsPong         = 0xa
+
+	wsCloseTimeout = 5 * time.Second
+)
+
+var (
+	// DefaultWebSocketReadLimit is the maximum size of messages read from
+	// WebSocket connections. Larger messages close the connection.
+	DefaultWebSocketReadLimit int64 = 32 << 20
+)
+
+// Error makes it compatible with `error` interface.
+func (e *WebSocketCloseError) Error() string {
+	return fmt.Sprintf("websocket closed: code=%d, reason=%s", e.Code, e.Reason)
+}
+
+// upgradeWebSocket performs the opening handshake of a WebSocket connection.
+func upgradeWebSocket(c Context) (*webSocketConn, error) {
+	req := c.Request()
+	if req.Method != http.MethodGet ||
+		!headerContainsToken(req.Header, HeaderConnection, "upgrade") ||
+		!headerContainsToken(req.Header, HeaderUpgrade, "websocket") {
+		return nil, NewHTTPError(http.StatusBadRequest, "not a websocket handshake")
+	}
+	if req.Header.Get(HeaderSecWebSocketVersion) != "13" {
+		c.Response().Header().Set(HeaderSecWebSocketVersion, "13")
+		return nil, NewHTTPError(http.StatusUpgradeRequired, "unsupported websocket version")
+	}
+	key := req.Header.Get(HeaderSecWebSocketKey)
+	if b, err := base64.StdEncoding.DecodeString(key); err != nil || len(b) != 16 {
+		return nil, NewHTTPError(http.StatusBadRequest, "invalid websocket key")
+	}
+	checkOrigin := c.Echo().WebSocketCheckOrigin
+	if checkOrigin == nil {
+		checkOrigin = sameOrigin
+	}
+	if !checkOrigin(c) {
+		return nil, NewHTTPError(http.StatusForbidden, "websocket origin not allowed")
+	}
+
+	res := c.Response()
+	if res.Committed {
+		return nil, errors.New("websocket upgrade after response was committed")
+	}
+	conn, rw, err := res.Hijack()
+	if err != nil {
+		return nil, err
+	}
+	subprotocol := res.Header().Get(HeaderSecWebSocketProtocol)
+	h := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + webSocketAccept(key) + "\r\n"
+	if subprotocol != "" {
+		h += "Sec-WebSocket-Protocol: " + subprotocol + "\r\n"
+	}
+	if _, err = rw.WriteString(h + "\r\n"); err == nil {
+		err = rw.Flush()
+	}
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	res.Status = http.StatusSwitchingProtocols
+	res.Committed = true
+	return &webSocketConn{
+		conn:        conn,
+		reader:      rw.Reader,
+		writer:      rw.Writer,
+		readLimit:   DefaultWebSocketReadLimit,
+		subprotocol: subprotocol,
+	}, nil
+}
+
+// sameOrigin reports whether the request has no `Origin` header, as sent by
+// non-browser clients, or one with the host of the request.
+func sameOrigin(c Context) bool {
+	origin := c.Request().Header.Get(HeaderOrigin)
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(u.Host, c.Request().Host)
+}
+
+func webSocketAccept(key string) string {
+	h := sha1.New()
+	h.Write([]byte(key + webSocketGUID))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// headerContainsToken reports whether the comma separated values of the header
+// contain token, case insensitively.
+func headerContainsToken(h http.Header, name, token string) bool {
+	for _, v := range h[http.CanonicalHeaderKey(name)] {
+		for _, t := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (c *webSocketConn) ReadMessage() (messageType int, data []byte, err error) {
+	for {
+		fin, opcode, payload, err := c.readFrame()
+		if err != nil {
+			return 0, nil, c.fail(err)
+		}
+		switch opcode {
+		case wsPing:
+			if err = c.writeFrame(wsPong, payload); err != nil {
+				return 0, nil, err
+			}
+			continue
+		case wsPong:
+			continue
+		case wsClose:
+			ce := &WebSocketCloseError{Code: WebSocketCloseNoStatus}
+			if len(payload) >= 2 {
+				ce.Code = int(binary.BigEndian.Uint16(payload))
+				ce.Reason = string(payload[2:])
+			}
+			code := ce.Code
+			if code == WebSocketCloseNoStatus {
+				code = WebSocketCloseNormal
+			}
+			c.writeClose(code, "")
+			c.conn.Close()
+			return 0, nil, ce
+		case WebSocketTextMessage, WebSocketBinaryMessage:
+			if messageType != 0 {
+				return 0, nil, c.fail(&WebSocketCloseError{WebSocketCloseProtocolError, "unexpected data frame"})
+			}
+			messageType = int(opcode)
+		case wsContinuation:
+			if messageType == 0 {
+				return 0, nil, c.fail(&WebSocketCloseError{WebSocketCloseProtocolError, "unexpected continuation frame"})
+			}
+		default:
+			return 0, nil, c.fail(&WebSocketCloseError{WebSocketCloseProtocolError, "unknown opcode"})
+		}
+
+		if int64(len(data)+len(payload)) > c.readLimit {
+			return 0, nil, c.fail(&WebSocketCloseError{WebSocketCloseMessageTooBig, "message too big"})
+		}
+		data = append(data, payload...)
+		if fin {
+			if messageType == WebSocketTextMessage && !utf8.Valid(data) {
+				return 0, nil, c.fail(&WebSocketCloseError{WebSocketCloseInvalidPayload, "invalid utf-8"})
+			}
+			return messageType, data, nil
+		}
+	}
+}
+
+// readFrame reads a frame sent by the client, which must be masked.
+func (c *webSocketConn) readFrame() (fin bool, opcode byte, payload []byte, err error) {
+	var h [2]byte
+	if _, err = io.ReadFull(c.reader, h[:]); err != nil {
+		return
+	}
+	fin = h[0]&0x80 != 0
+	opcode = h[0] & 0x0f
+	if h[0]&0x70 != 0 {
+		err = &WebSocketCloseError{WebSocketCloseProtocolError, "reserved bits set"}
+		return
+	}
+	if h[1]&0x80 == 0 {
+		err = &WebSocketCloseError{WebSocketCloseProtocolError, "unmasked client frame"}
+		return
+	}
+	length := int64(h[1] & 0x7f)
+	if opcode >= wsClose && (length > 125 || !fin) {
+		err = &WebSocketCloseError{WebSocketCloseProtocolError, "invalid control frame"}
+		return
+	}
+	switch length {
+	case 126:
+		var b [2]byte
+		if _, err = io.ReadFull(c.reader, b[:]); err != nil {
+			return
+		}
+		length = int64(binary.BigEndian.Uint16(b[:]))
+	case 127:
+		var b [8]byte
+		if _, err = io.ReadFull(c.reader, b[:]); err != nil {
+			return
+		}
+		length = int64(binary.BigEndian.Uint64(b[:]))
+	}
+	if length < 0 || length > c.readLimit {
+		err = &WebSocketCloseError{WebSocketCloseMessageTooBig, "message too big"}
+		return
+	}
+	var mask [4]byte
+	if _, err = io.ReadFull(c.reader, mask[:]); err != nil {
+		return
+	}
+	// The payload grows as it arrives, instead of being allocated at the
+	// announced length upfront.
+	buf := new(bytes.Buffer)
+	var n int64
+	if n, err = io.CopyN(buf, c.reader, length); err != nil {
+		if err == io.EOF && n < length {
+			err = io.ErrUnexpectedEOF
+		}
+		return
+	}
+	payload = buf.Bytes()
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return
+}
+
+// fail closes the connection because of err, telling the peer the reason for
+// protocol errors.
+func (c *webSocketConn) fail(err error) error {
+	if ce, ok := err.(*WebSocketCloseError); ok {
+		c.writeClose(ce.Code, ce.Reason)
+	}
+	c.conn.Close()
+	return err
+}
+
+func (c *webSocketConn) WriteMessage(messageType int, data []byte) error {
+	if messageType != WebSocketTextMessage && messageType != WebSocketBinaryMessage {
+		return fmt.Errorf("invalid websocket message type %d", messageType)
+	}
+	return c.writeFrame(byte(messageType), data)
+}
+
+func (c *webSocketConn) Ping(data []byte) error {
+	if len(data) > 125 {
+		return errors.New("websocket ping data too long")
+	}
+	return c.writeFrame(wsPing, data)
+}
+
+func (c *webSocketConn) Close(code int, reason string) error {
+	if err := c.writeClose(code, reason); err != nil {
+		c.conn.Close()
+		return err
+	}
+	// Wait for the close frame of the peer
+	c.conn.SetReadDeadline(time.Now().Add(wsCloseTimeout))
+	for {
+		_, opcode, _, err := c.readFrame()
+		if err != nil || opcode == wsClose {
+			break
+		}
+	}
+	return c.conn.Close()
+}
+
+func (c *webSocketConn) Subprotocol() string {
+	return c.subprotocol
+}
+
+func (c *webSocketConn) RemoteAddr() net.Addr {
+	return c.conn.RemoteAddr()
+}
+
+// writeClose sends a close frame, unless one was sent already.
+func (c *webSocketConn) writeClose(code int, reason string) error {
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+	if c.closeSent {
+		return nil
+	}
+	c.closeSent = true
+	payload := make([]byte, 2, 2+len(reason))
+	binary.BigEndian.PutUint16(payload, uint16(code))
+	if len(reason) > 123 {
+		reason = reason[:123]
+	}
+	return c.write(wsClose, append(payload, reason...))
+}
+
+func (c *webSocketConn) writeFrame(opcode byte, payload []byte) error {
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+	if c.closeSent {
+		return &WebSocketCloseError{WebSocketCloseNormal, "close sent"}
+	}
+	return c.write(opcode, payload)
+}
+
+// write writes an unmasked final frame. The write mutex must be held.
+func (c *webSocketConn) write(opcode byte, payload []byte) error {
+	h := []byte{0x80 | opcode, 0}
+	switch n := len(payload); {
+	case n <= 125:
+		h[1] = byte(n)
+	case n <= 0xffff:
+		h[1] = 126
+		h = append(h, 0, 0)
+		binary.BigEndian.PutUint16(h[2:], uint16(n))
+	default:
+		h[1] = 127
+		h = append(h, 0, 0, 0, 0, 0, 0, 0, 0)
+		binary.BigEndian.PutUint64(h[2:], uint64(n))
+	}
+	if _, err := c.writer.Write(h); err != nil {
+		return err
+	}
+	if _, err := c.writer.Write(payload); err != nil {
+		return err
+	}
+	return c.writer.Flush()
+}
@@ -0,0 +1,266 @@
+package echo
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testWebSocketClient struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+func dialTestWebSocket(t *testing.T, url string, headers string) (*testWebSocketClient, *http.Response) {
+	conn, err := net.Dial("tcp", strings.TrimPrefix(url, "http://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	io.WriteString(conn, "GET / HTTP/1.1\r\nHost: localhost\r\n"+
+		"Upgrade: websocket\r\nConnection: keep-alive, Upgrade\r\n"+
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\nSec-WebSocket-Version: 13\r\n"+
+		headers+"\r\n")
+	reader := bufio.NewReader(conn)
+	res, err := http.ReadResponse(reader, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testWebSocketClient{conn: conn, reader: reader}, res
+}
+
+func (c *testWebSocketClient) write(opcode byte, fin bool, payload []byte) {
+	h := []byte{opcode, 0x80}
+	if fin {
+		h[0] |= 0x80
+	}
+	if len(payload) < 126 {
+		h[1] |= byte(len(payload))
+	} else {
+		h[1] |= 126
+		h = append(h, 0, 0)
+		binary.BigEndian.PutUint16(h[2:], uint16(len(payload)))
+	}
+	mask := []byte{1, 2, 3, 4}
+	masked := make([]byte, len(payload))
+	for i := range payload {
+		masked[i] = payload[i] ^ mask[i%4]
+	}
+	c.conn.Write(append(append(h, mask...), masked...))
+}
+
+func (c *testWebSocketClient) read() (opcode byte, payload []byte) {
+	var h [2]byte
+	io.ReadFull(c.reader, h[:])
+	n := int(h[1] & 0x7f)
+	if n == 126 {
+		var b [2]byte
+		io.ReadFull(c.reader, b[:])
+		n = int(binary.BigEndian.Uint16(b[:]))
+	}
+	payload = make([]byte, n)
+	io.ReadFull(c.reader, payload)
+	return h[0] & 0x0f, payload
+}
+
+func closePayload(code int, reason string) []byte {
+	b := make([]byte, 2)
+	binary.BigEndian.PutUint16(b, uint16(code))
+	return append(b, reason...)
+}
+
+func TestWebSocket(t *testing.T) {
+	e := New()
+	closed := make(chan error, 1)
+	e.GET("/", func(c Context) error {
+		c.WebSocketSubprotocol("chat")
+		return c.WebSocket(func(ws WebSocketConn) error {
+			assert.Equal(t, "chat", ws.Subprotocol())
+			for {
+				typ, msg, err := ws.ReadMessage()
+				if err != nil {
+					closed <- err
+					return nil
+				}
+				if err = ws.WriteMessage(typ, append([]byte("echo: "), msg...)); err != nil {
+					return err
+				}
+			}
+		})
+	})
+	srv := httptest.NewServer(e)
+	defer srv.Close()
+
+	client, res := dialTestWebSocket(t, srv.URL, "Sec-WebSocket-Protocol: chat, superchat\r\n")
+	defer client.conn.Close()
+	assert.Equal(t, http.StatusSwitchingProtocols, res.StatusCode)
+	assert.Equal(t, "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=", res.Header.Get(HeaderSecWebSocketAccept))
+	assert.Equal(t, "chat", res.Header.Get(HeaderSecWebSocketProtocol))
+
+	// Text message
+	client.write(WebSocketTextMessage, true, []byte("hello"))
+	opcode, payload := client.read()
+	assert.Equal(t, byte(WebSocketTextMessage), opcode)
+	assert.Equal(t, "echo: hello", string(payload))
+
+	// Fragmented binary message with a ping in between
+	client.write(WebSocketBinaryMessage, false, []byte("ab"))
+	client.write(wsPing, true, []byte("p"))
+	client.write(wsContinuation, true, []byte(strings.Repeat("c", 200)))
+	opcode, payload = client.read()
+	assert.Equal(t, byte(wsPong), opcode)
+	assert.Equal(t, "p", string(payload))
+	opcode, payload = client.read()
+	assert.Equal(t, byte(WebSocketBinaryMessage), opcode)
+	assert.Equal(t, "echo: ab"+strings.Repeat("c", 200), string(payload))
+
+	// Close handshake
+	client.write(wsClose, true, closePayload(WebSocketCloseGoingAway, "bye"))
+	opcode, payload = client.read()
+	assert.Equal(t, byte(wsClose), opcode)
+	assert.Equal(t, closePayload(WebSocketCloseGoingAway, ""), payload)
+	err := <-closed
+	if assert.IsType(t, new(WebSocketCloseError), err) {
+		assert.Equal(t, WebSocketCloseGoingAway, err.(*WebSocketCloseError).Code)
+		assert.Equal(t, "bye", err.(*WebSocketCloseError).Reason)
+	}
+}
+
+func TestWebSocketServerClose(t *testing.T) {
+	e := New()
+	e.GET("/", func(c Context) error {
+		return c.WebSocket(func(ws WebSocketConn) error {
+			return ws.WriteMessage(WebSocketTextMessage, []byte("hi"))
+		})
+	})
+	srv := httptest.NewServer(e)
+	defer srv.Close()
+
+	client, res := dialTestWebSocket(t, srv.URL, "")
+	defer client.conn.Close()
+	assert.Equal(t, http.StatusSwitchingProtocols, res.StatusCode)
+	assert.Empty(t, res.Header.Get(HeaderSecWebSocketProtocol))
+
+	opcode, payload := client.read()
+	assert.Equal(t, byte(WebSocketTextMessage), opcode)
+	assert.Equal(t, "hi", string(payload))
+
+	// The connection is closed after the handler returns
+	opcode, payload = client.read()
+	assert.Equal(t, byte(wsClose), opcode)
+	assert.Equal(t, closePayload(WebSocketCloseNormal, ""), payload)
+	client.write(wsClose, true, payload)
+	_, err := client.reader.ReadByte()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestWebSocketProtocolError(t *testing.T) {
+	e := New()
+	closed := make(chan error, 1)
+	e.GET("/", func(c Context) error {
+		return c.WebSocket(func(ws WebSocketConn) error {
+			_, _, err := ws.ReadMessage()
+			closed <- err
+			return nil
+		})
+	})
+	srv := httptest.NewServer(e)
+	defer srv.Close()
+
+	client, _ := dialTestWebSocket(t, srv.URL, "")
+	defer client.conn.Close()
+	client.write(WebSocketTextMessage, true, []byte{0xff, 0xfe})
+	opcode, payload := client.read()
+	assert.Equal(t, byte(wsClose), opcode)
+	assert.Equal(t, WebSocketCloseInvalidPayload, int(binary.BigEndian.Uint16(payload)))
+	err := <-closed
+	if assert.IsType(t, new(WebSocketCloseError), err) {
+		assert.Equal(t, WebSocketCloseInvalidPayload, err.(*WebSocketCloseError).Code)
+	}
+}
+
+func TestWebSocketInvalidHandshake(t *testing.T) {
+	e := New()
+	h := func(c Context) error {
+		return c.WebSocket(func(ws WebSocketConn) error {
+			return nil
+		})
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	c := e.NewContext(req, httptest.NewRecorder())
+	he := h(c).(*HTTPError)
+	assert.Equal(t, http.StatusBadRequest, he.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(HeaderUpgrade, "websocket")
+	req.Header.Set(HeaderConnection, "Upgrade")
+	req.Header.Set(HeaderSecWebSocketVersion, "8")
+	rec := httptest.NewRecorder()
+	c = e.NewContext(req, rec)
+	he = h(c).(*HTTPError)
+	assert.Equal(t, http.StatusUpgradeRequired, he.Code)
+	assert.Equal(t, "13", rec.Header().Get(HeaderSecWebSocketVersion))
+
+	req.Header.Set(HeaderSecWebSocketVersion, "13")
+	req.Header.Set(HeaderSecWebSocketKey, "short")
+	c = e.NewContext(req, httptest.NewRecorder())
+	he = h(c).(*HTTPError)
+	assert.Equal(t, http.StatusBadRequest, he.Code)
+	assert.Equal(t, "invalid websocket key", he.Message)
+}
+
+func TestWebSocketCheckOrigin(t *testing.T) {
+	e := New()
+	e.GET("/", func(c Context) error {
+		return c.WebSocket(func(ws WebSocketConn) error {
+			return nil
+		})
+	})
+	srv := httptest.NewServer(e)
+	defer srv.Close()
+	handshake := func(headers string) int {
+		client, res := dialTestWebSocket(t, srv.URL, headers)
+		client.conn.Close()
+		return res.StatusCode
+	}
+
+	assert.Equal(t, http.StatusSwitchingProtocols, handshake(""))
+	assert.Equal(t, http.StatusSwitchingProtocols, handshake("Origin: http://localhost\r\n"))
+	assert.Equal(t, http.StatusForbidden, handshake("Origin: https://evil.com\r\n"))
+
+	e.WebSocketCheckOrigin = func(c Context) bool {
+		return c.Request().Header.Get(HeaderOrigin) == "https://app.com"
+	}
+	assert.Equal(t, http.StatusSwitchingProtocols, handshake("Origin: https://app.com\r\n"))
+	assert.Equal(t, http.StatusForbidden, handshake("Origin: http://localhost\r\n"))
+}
+
+func TestWebSocketTruncatedFrame(t *testing.T) {
+	e := New()
+	read := make(chan error, 1)
+	e.GET("/", func(c Context) error {
+		return c.WebSocket(func(ws WebSocketConn) error {
+			_, _, err := ws.ReadMessage()
+			read <- err
+			return nil
+		})
+	})
+	srv := httptest.NewServer(e)
+	defer srv.Close()
+
+	client, _ := dialTestWebSocket(t, srv.URL, "")
+	// A frame announcing 16MB with a few bytes of payload
+	h := []byte{0x80 | WebSocketBinaryMessage, 0x80 | 127, 0, 0, 0, 0, 1, 0, 0, 0, 1, 2, 3, 4}
+	client.conn.Write(append(h, "test"...))
+	client.conn.Close()
+	assert.Equal(t, io.ErrUnexpectedEOF, <-read)
+}